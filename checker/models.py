@@ -26,6 +26,18 @@ class Game:
     players_count: int
     max_players: int
     players: list[User]
+    no_guess: bool = False
+    fair: bool = False
+    hints: int = 0
+    topology: str = "square"
+    questions: bool = False
+    scoring: bool = False
+    lives: int = 1
+    coop: bool = False
+    solo: bool = False
+    seed: Optional[int] = None
+    daily: Optional[str] = None
+    commitments: Optional[list[dict]] = None
 
 @dataclass
 class Cell:
@@ -47,6 +59,13 @@ class Participant:
     username: str
     is_owner: bool
     field: Optional[Field] = None
+    moves: int = 0
+    hints_used: int = 0
+    penalty_until: Optional[str] = None
+    bot: Optional[str] = None
+    score: int = 0
+    started_at: Optional[str] = None
+    cells_opened: int = 0
 
 class EventType(StrEnum):
     TYPE_AUTH = "AUTH"
@@ -68,6 +87,7 @@ class Event:
     message: Optional[str] = None
     payload: Optional[dict] = None
     error: Optional[str] = None
+    seq: Optional[int] = None
 
 @dataclass
 class Message:
@@ -75,4 +95,6 @@ class Message:
     creator_id: int
     creator_username: str
     text: str
-    created_at: str
\ No newline at end of file
+    created_at: str
+    edited_at: Optional[str] = None
+    deleted: bool = False
\ No newline at end of file
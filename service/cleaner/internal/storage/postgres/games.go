@@ -5,8 +5,11 @@ import (
 	"time"
 )
 
+// DeleteGamesBefore удаляет игры, созданные раньше t. Игры ещё не сыгранных матчей турниров не удаляются,
+// их закрывает сам турнир по истечении раунда
 func (s *Storage) DeleteGamesBefore(ctx context.Context, t time.Time) (int64, error) {
-	res, err := s.DB.Exec(ctx, "DELETE FROM games WHERE created_at < $1", t)
+	res, err := s.DB.Exec(ctx, `DELETE FROM games WHERE created_at < $1
+	AND id NOT IN (SELECT game_id FROM tournament_matches WHERE game_id IS NOT NULL AND status = 'pending')`, t)
 	if err != nil {
 		return 0, err
 	}
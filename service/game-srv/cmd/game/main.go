@@ -7,7 +7,10 @@ import (
 	"ms4me/game/internal/config"
 	handlers "ms4me/game/internal/http/handlers"
 	"ms4me/game/internal/services/auth"
+	"ms4me/game/internal/services/daily"
+	"ms4me/game/internal/services/friend"
 	"ms4me/game/internal/services/game"
+	"ms4me/game/internal/services/tournament"
 	"ms4me/game/internal/storage/postgres"
 	"ms4me/game/internal/storage/redis"
 	ingameclient "ms4me/game/pkg/ingame_client"
@@ -37,7 +40,11 @@ func main() {
 	gameSocketClient := ingameclient.New(cfg.IngameConfig)
 	gameService := game.New(log, db, rdb, gameSocketClient)
 	authSrv := auth.New(log, db, []byte(cfg.JwtSecret), cfg.JwtTTL)
-	gameHandlers := handlers.New(log, gameService, authSrv, cfg)
+	tournamentService := tournament.New(log, db, gameService, rdb)
+	dailyService := daily.New(log, db, gameService)
+	friendService := friend.New(log, db, rdb, gameSocketClient)
+	gameHandlers := handlers.New(log, gameService, tournamentService, dailyService, friendService, authSrv, cfg)
+	go tournamentService.Run(appContext)
 
 	application := app.New(cfg.ApplicationConfig, db, log, gameHandlers)
 	log.Info("Starting app", slog.Any("config", cfg))
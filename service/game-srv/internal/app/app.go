@@ -84,6 +84,7 @@ func (a *App) SetupRouter(h *handlers.GameHandlers) http.Handler {
 		r.Post("/login", h.Login())
 		r.Get("/logout", h.Logout())
 		r.Get("/game", mw.Auth()(h.GetMyGames()).ServeHTTP)
+		r.Get("/stats", mw.Auth()(h.GetMyStats()).ServeHTTP)
 	})
 
 	router.Route("/api/v1/game", func(gameRouter chi.Router) {
@@ -95,14 +96,46 @@ func (a *App) SetupRouter(h *handlers.GameHandlers) http.Handler {
 		gameRouter.Delete("/{id}", h.DeleteGame())
 		gameRouter.Post("/{id}/start", h.StartGame())
 		gameRouter.Post("/{id}/enter", h.EnterGame())
+		gameRouter.Post("/{id}/bot", h.AddBot())
 		gameRouter.Post("/{id}/exit", h.ExitGame())
 
 		gameRouter.Get("/{id}/congratulation", h.GetCongratulation())
 	})
 
+	router.Route("/api/v1/tournament", func(tournamentRouter chi.Router) {
+		tournamentRouter.Use(mw.Auth())
+		tournamentRouter.Post("/", h.CreateTournament())
+		tournamentRouter.Get("/", h.GetTournaments())
+		tournamentRouter.Get("/{id}", h.GetTournament())
+		tournamentRouter.Post("/{id}/register", h.RegisterTournament())
+		tournamentRouter.Post("/{id}/start", h.StartTournament())
+	})
+
+	router.Route("/api/v1/daily", func(dailyRouter chi.Router) {
+		dailyRouter.Use(mw.Auth())
+		dailyRouter.Get("/", h.GetDaily())
+		dailyRouter.Post("/play", h.PlayDaily())
+		dailyRouter.Get("/leaderboard", h.GetDailyLeaderboard())
+	})
+
+	router.Route("/api/v1/friend", func(friendRouter chi.Router) {
+		friendRouter.Use(mw.Auth())
+		friendRouter.Get("/", h.GetFriends())
+		friendRouter.Post("/", h.AddFriend())
+		friendRouter.Post("/{id}/accept", h.AcceptFriend())
+		friendRouter.Delete("/{id}", h.RemoveFriend())
+		friendRouter.Get("/{id}/messages", h.GetDirectMessages())
+		friendRouter.Post("/{id}/messages", h.SendDirectMessage())
+		friendRouter.Post("/{id}/invite", h.InviteFriend())
+	})
+
 	router.Route("/api/v1/internal", func(r chi.Router) {
+		r.Get("/game/{id}", h.GameSettings())
 		r.Get("/game/{id}/status", h.GameStatus())
 		r.Post("/game/{id}/close", h.CloseGame())
+		r.Post("/game/{id}/commitment", h.SaveCommitment())
+		r.Get("/game/{id}/chat", h.GetChat())
+		r.Post("/game/{id}/chat", h.ArchiveChat())
 	})
 
 	router.Get("/api/v1/health", handlers.Health())
@@ -0,0 +1,45 @@
+package dailydto
+
+import (
+	"errors"
+	"ms4me/game/internal/http/dto/response"
+	"ms4me/game/internal/models"
+	"net/url"
+	"time"
+)
+
+var ErrDay = errors.New("day should be date in format YYYY-MM-DD")
+
+type GetDailyResponse struct {
+	response.Response
+	Daily *models.DailyChallenge `json:"daily"`
+}
+
+type PlayDailyResponse struct {
+	response.Response
+	ID string `json:"id"`
+}
+
+type GetLeaderboardRequest struct {
+	Day time.Time
+}
+
+type GetLeaderboardResponse struct {
+	response.Response
+	Day         string                 `json:"day"`
+	Leaderboard []*models.DailyAttempt `json:"leaderboard"`
+}
+
+// Render разбирает день таблицы лидеров из query, по умолчанию берётся today
+func (r *GetLeaderboardRequest) Render(values url.Values, today time.Time) error {
+	r.Day = today
+	if !values.Has("day") {
+		return nil
+	}
+	day, err := time.Parse(time.DateOnly, values.Get("day"))
+	if err != nil {
+		return ErrDay
+	}
+	r.Day = day
+	return nil
+}
@@ -0,0 +1,91 @@
+package frienddto
+
+import (
+	"errors"
+	"ms4me/game/internal/http/dto/response"
+	"ms4me/game/internal/models"
+	"net/url"
+	"strconv"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+const defaultMessagesLimit = 50
+const maxMessagesLimit = 100
+
+var (
+	ErrMessagesLimit  = errors.New("limit should be number from 1 to 100")
+	ErrMessagesBefore = errors.New("before should be message id")
+)
+
+type AddFriendRequest struct {
+	Username string `json:"username" validate:"required,max=150"`
+}
+
+type AddFriendResponse struct {
+	response.Response
+	Accepted bool `json:"accepted"` // была встречная заявка, пользователи сразу стали друзьями
+}
+
+type GetFriendsResponse struct {
+	response.Response
+	Friends []*models.Friend `json:"friends"`
+}
+
+type SendMessageRequest struct {
+	Text string `json:"text" validate:"required,max=512"`
+}
+
+type SendMessageResponse struct {
+	response.Response
+	Message *models.DirectMessage `json:"message"`
+}
+
+type GetMessagesRequest struct {
+	Before int64
+	Limit  int
+}
+
+type GetMessagesResponse struct {
+	response.Response
+	Messages []*models.DirectMessage `json:"messages"`
+}
+
+type InviteRequest struct {
+	GameID string `json:"game_id" validate:"required,uuid"`
+}
+
+func (r *AddFriendRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *SendMessageRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *InviteRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// Render разбирает курсор before и размер страницы limit из query
+func (r *GetMessagesRequest) Render(values url.Values) error {
+	r.Limit = defaultMessagesLimit
+	if values.Has("limit") {
+		limit, err := strconv.Atoi(values.Get("limit"))
+		if err != nil || limit < 1 || limit > maxMessagesLimit {
+			return ErrMessagesLimit
+		}
+		r.Limit = limit
+	}
+	if values.Has("before") {
+		before, err := strconv.ParseInt(values.Get("before"), 10, 64)
+		if err != nil || before < 1 {
+			return ErrMessagesBefore
+		}
+		r.Before = before
+	}
+	return nil
+}
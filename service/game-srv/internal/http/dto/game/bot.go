@@ -0,0 +1,14 @@
+package gamedto
+
+import (
+	validator "github.com/go-playground/validator/v10"
+)
+
+type AddBotRequest struct {
+	Level string `json:"level" validate:"required,oneof=easy medium hard"`
+}
+
+func (r *AddBotRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
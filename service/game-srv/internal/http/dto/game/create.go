@@ -14,11 +14,29 @@ var (
 	ErrRows       = errors.New("rows should be > 0")
 )
 
+// defaultHints количество подсказок на участника, если не указано при создании игры
+const defaultHints = 3
+
+// defaultTopology топология поля, если не указана при создании игры
+const defaultTopology = "square"
+
+// defaultLives количество жизней, если не указано при создании игры: первая мина заканчивает игру
+const defaultLives = 1
+
 type CreateGameRequest struct {
 	Title string `json:"title" validate:"required,max=64"`
 	// Rows     int    `json:"rows" validate:"required"`
 	// Cols     int    `json:"cols" validate:"required"`
-	IsPublic *bool `json:"is_public,omitempty"`
+	IsPublic  *bool  `json:"is_public,omitempty"`
+	NoGuess   bool   `json:"no_guess"`                              // поле генерируется так, чтобы его можно было пройти без угадывания
+	Fair      bool   `json:"fair"`                                  // все участники получают одинаковое поле
+	Questions bool   `json:"questions"`                             // после флага можно поставить знак вопроса
+	Scoring   bool   `json:"scoring" validate:"excluded_with=Solo"` // игра на очки
+	Coop      bool   `json:"coop" validate:"excluded_with=Solo"`    // совместная игра на одном общем поле
+	Solo      bool   `json:"solo"`                                  // одиночная игра без лобби, начинается сразу
+	Hints     *int   `json:"hints,omitempty" validate:"omitempty,gte=0,lte=10"`
+	Topology  string `json:"topology,omitempty" validate:"omitempty,oneof=square torus hex"` // по умолчанию square
+	Lives     int    `json:"lives,omitempty" validate:"gte=1,lte=5"`                         // сколько мин можно открыть, по умолчанию 1
 }
 
 type CreateGameResponse struct {
@@ -31,6 +49,16 @@ func (r *CreateGameRequest) Validate() error {
 		value := true
 		r.IsPublic = &value
 	}
+	if r.Hints == nil {
+		value := defaultHints
+		r.Hints = &value
+	}
+	if r.Topology == "" {
+		r.Topology = defaultTopology
+	}
+	if r.Lives == 0 {
+		r.Lives = defaultLives
+	}
 	validate := validator.New()
 	return validate.Struct(r)
 }
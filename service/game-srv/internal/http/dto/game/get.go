@@ -60,10 +60,32 @@ type GameStatusResponse struct {
 }
 
 type CloseGameRequest struct {
-	WinnerID int64 `json:"winner_id"`
+	WinnerID int64                          `json:"winner_id"`         // 0, если победителя нет
+	Reveals  map[string]*models.FieldReveal `json:"reveals,omitempty"` // раскрытие полей участников по id
+	Hints    map[string]int                 `json:"hints,omitempty"`   // количество использованных подсказок по id
+	Stats    map[string]*models.PlayerStats `json:"stats,omitempty"`   // итоги участников по id
+}
+
+type SaveCommitmentRequest struct {
+	UserID     int64  `json:"user_id"`
+	Commitment string `json:"commitment"`
+}
+
+type ArchiveChatRequest struct {
+	Messages []*models.ChatMessage `json:"messages"`
+}
+
+type GetChatResponse struct {
+	response.Response
+	Messages []*models.ChatMessage `json:"messages"`
 }
 
 type GetCongratulationResponse struct {
 	response.Response
 	Congratulation string `json:"congratulation"`
 }
+
+type UserStatsResponse struct {
+	response.Response
+	Stats *models.UserStats `json:"stats"`
+}
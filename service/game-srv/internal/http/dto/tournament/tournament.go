@@ -0,0 +1,44 @@
+package tournamentdto
+
+import (
+	"ms4me/game/internal/http/dto/response"
+	"ms4me/game/internal/models"
+
+	validator "github.com/go-playground/validator/v10"
+)
+
+// defaultRoundMinutes время на раунд, если не указано при создании турнира
+const defaultRoundMinutes = 15
+
+type CreateTournamentRequest struct {
+	Title        string `json:"title" validate:"required,max=48"`
+	Format       string `json:"format" validate:"required,oneof=single_elimination swiss"`
+	Preset       string `json:"preset,omitempty" validate:"omitempty,oneof=classic no_guess torus hex"` // по умолчанию classic
+	RoundMinutes int    `json:"round_minutes,omitempty" validate:"gte=1,lte=120"`
+}
+
+type CreateTournamentResponse struct {
+	response.Response
+	ID string `json:"id"`
+}
+
+type GetTournamentsResponse struct {
+	response.Response
+	Tournaments []*models.Tournament `json:"tournaments"`
+}
+
+type GetTournamentResponse struct {
+	response.Response
+	Tournament *models.TournamentDetails `json:"tournament"`
+}
+
+func (r *CreateTournamentRequest) Validate() error {
+	if r.Preset == "" {
+		r.Preset = "classic"
+	}
+	if r.RoundMinutes == 0 {
+		r.RoundMinutes = defaultRoundMinutes
+	}
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	dailydto "ms4me/game/internal/http/dto/daily"
+	"ms4me/game/internal/http/dto/response"
+	"ms4me/game/internal/http/middlewares"
+	"ms4me/game/internal/services/daily"
+	"ms4me/game/internal/storage"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// GetDaily отдаёт описание испытания дня и попытку текущего пользователя
+func (gr *GameHandlers) GetDaily() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		challenge, err := gr.dailySrv.Challenge(ctx, user.ID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, dailydto.GetDailyResponse{
+			Response: response.OK(),
+			Daily:    challenge,
+		})
+	}
+}
+
+func (gr *GameHandlers) PlayDaily() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		id, err := gr.dailySrv.Play(ctx, user.ID)
+		if err != nil {
+			for _, known := range []error{storage.ErrDailyAlreadyPlayed, storage.ErrAlreadyPlaying} {
+				if errors.Is(err, known) {
+					w.WriteHeader(http.StatusBadRequest)
+					render.JSON(w, r, response.Error(known.Error()))
+					return
+				}
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, dailydto.PlayDailyResponse{
+			Response: response.OK(),
+			ID:       id,
+		})
+	}
+}
+
+func (gr *GameHandlers) GetDailyLeaderboard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+
+		var req dailydto.GetLeaderboardRequest
+		if err := req.Render(r.URL.Query(), daily.Today()); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		leaderboard, err := gr.dailySrv.Leaderboard(ctx, req.Day)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, dailydto.GetLeaderboardResponse{
+			Response:    response.OK(),
+			Day:         req.Day.Format(time.DateOnly),
+			Leaderboard: leaderboard,
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"errors"
+	frienddto "ms4me/game/internal/http/dto/friend"
+	"ms4me/game/internal/http/dto/response"
+	"ms4me/game/internal/http/middlewares"
+	"ms4me/game/internal/services/friend"
+	"ms4me/game/internal/storage"
+	"ms4me/game/pkg/lib/validator"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+var ErrInvalidFriendID = response.Error("id друга должен быть числом")
+
+func (gr *GameHandlers) GetFriends() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		friends, err := gr.friendSrv.List(ctx, user.ID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, frienddto.GetFriendsResponse{
+			Response: response.OK(),
+			Friends:  friends,
+		})
+	}
+}
+
+func (gr *GameHandlers) AddFriend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		var req frienddto.AddFriendRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrInvalidBody)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, response.Error(validator.GetDetailedError(err).Error()))
+			return
+		}
+
+		accepted, err := gr.friendSrv.Request(ctx, user.ID, req.Username)
+		if err != nil {
+			if errors.Is(err, storage.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, ErrUserNotFound)
+				return
+			}
+			writeFriendError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, frienddto.AddFriendResponse{
+			Response: response.OK(),
+			Accepted: accepted,
+		})
+	}
+}
+
+func (gr *GameHandlers) AcceptFriend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		friendID, ok := friendIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		if err := gr.friendSrv.Accept(ctx, user.ID, friendID); err != nil {
+			writeFriendError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// RemoveFriend удаляет друга, отклоняет входящую или отзывает исходящую заявку
+func (gr *GameHandlers) RemoveFriend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		friendID, ok := friendIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		if err := gr.friendSrv.Remove(ctx, user.ID, friendID); err != nil {
+			writeFriendError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+func (gr *GameHandlers) GetDirectMessages() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		friendID, ok := friendIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		var req frienddto.GetMessagesRequest
+		if err := req.Render(r.URL.Query()); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+			return
+		}
+
+		messages, err := gr.friendSrv.Messages(ctx, user.ID, friendID, req.Before, req.Limit)
+		if err != nil {
+			writeFriendError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, frienddto.GetMessagesResponse{
+			Response: response.OK(),
+			Messages: messages,
+		})
+	}
+}
+
+func (gr *GameHandlers) SendDirectMessage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		friendID, ok := friendIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		var req frienddto.SendMessageRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrInvalidBody)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, response.Error(validator.GetDetailedError(err).Error()))
+			return
+		}
+
+		message, err := gr.friendSrv.SendMessage(ctx, user.ID, user.Username, friendID, req.Text)
+		if err != nil {
+			writeFriendError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, frienddto.SendMessageResponse{
+			Response: response.OK(),
+			Message:  message,
+		})
+	}
+}
+
+// InviteFriend отправляет другу приглашение в игру пользователя
+func (gr *GameHandlers) InviteFriend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		friendID, ok := friendIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		var req frienddto.InviteRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrInvalidBody)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, response.Error(validator.GetDetailedError(err).Error()))
+			return
+		}
+
+		err := gr.friendSrv.Invite(ctx, user.ID, user.Username, friendID, req.GameID)
+		if err != nil {
+			if errors.Is(err, storage.ErrGameNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, response.Error(storage.ErrGameNotFound.Error()))
+				return
+			}
+			writeFriendError(w, r, err)
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+func friendIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	friendID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, ErrInvalidFriendID)
+		return 0, false
+	}
+	return friendID, true
+}
+
+func writeFriendError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, storage.ErrFriendNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, response.Error(storage.ErrFriendNotFound.Error()))
+		return
+	}
+	for _, known := range []error{storage.ErrFriendRequestExists, storage.ErrAlreadyFriends, friend.ErrFriendYourself, friend.ErrGameIsNotOpen} {
+		if errors.Is(err, known) {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, response.Error(known.Error()))
+			return
+		}
+	}
+	for _, known := range []error{friend.ErrNotFriends, friend.ErrNotGameParticipant} {
+		if errors.Is(err, known) {
+			w.WriteHeader(http.StatusForbidden)
+			render.JSON(w, r, response.Error(known.Error()))
+			return
+		}
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	render.JSON(w, r, response.ErrInternalError)
+}
@@ -38,6 +38,10 @@ func (gr *GameHandlers) CreateGame() http.HandlerFunc {
 				render.JSON(w, r, response.Error(storage.ErrAlreadyCreatedGame.Error()))
 				return
 			}
+			if errors.Is(err, storage.ErrAlreadyPlaying) {
+				render.JSON(w, r, response.Error(storage.ErrAlreadyPlaying.Error()))
+				return
+			}
 			render.JSON(w, r, response.ErrInternalError)
 			return
 		}
@@ -131,6 +135,10 @@ func (gr *GameHandlers) UpdateGame() http.HandlerFunc {
 				render.JSON(w, r, response.Error(storage.ErrGameNotFoundOrNotYourOwn.Error()))
 				return
 			}
+			if errors.Is(err, game.ErrGameIsNotOpen) {
+				render.JSON(w, r, response.Error(game.ErrGameIsNotOpen.Error()))
+				return
+			}
 			render.JSON(w, r, response.ErrInternalError)
 			return
 		}
@@ -263,6 +271,58 @@ func (gr *GameHandlers) EnterGame() http.HandlerFunc {
 	}
 }
 
+func (gr *GameHandlers) AddBot() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			render.JSON(w, r, ErrEmptyID)
+			return
+		}
+
+		var req gamedto.AddBotRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			render.JSON(w, r, ErrInvalidBody)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			render.JSON(w, r, response.Error(validator.GetDetailedError(err).Error()))
+			return
+		}
+
+		err := gr.gameSrv.AddBot(ctx, id, user.ID, req.Level)
+		if err != nil {
+			if errors.Is(err, game.ErrOnlyOwnerCanAddBot) {
+				render.JSON(w, r, response.Error(game.ErrOnlyOwnerCanAddBot.Error()))
+				return
+			}
+			if errors.Is(err, storage.ErrMaxPlayers) {
+				render.JSON(w, r, response.Error(storage.ErrMaxPlayers.Error()))
+				return
+			}
+			if errors.Is(err, storage.ErrPlayerAlreadyExists) {
+				render.JSON(w, r, response.Error(storage.ErrPlayerAlreadyExists.Error()))
+				return
+			}
+			if errors.Is(err, game.ErrGameIsNotOpen) {
+				render.JSON(w, r, response.Error(game.ErrGameIsNotOpen.Error()))
+				return
+			}
+			if errors.Is(err, storage.ErrGameNotFound) {
+				render.JSON(w, r, response.Error(storage.ErrGameNotFound.Error()))
+				return
+			}
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
 func (gr *GameHandlers) ExitGame() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -319,6 +379,26 @@ func (gr *GameHandlers) GetMyGames() http.HandlerFunc {
 	}
 }
 
+// GetMyStats отдаёт личную статистику пользователя по законченным играм
+func (gr *GameHandlers) GetMyStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+		w.Header().Add("Content-Type", "application/json")
+
+		stats, err := gr.gameSrv.UserStats(ctx, user.ID)
+		if err != nil {
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, gamedto.UserStatsResponse{
+			Response: response.OK(),
+			Stats:    stats,
+		})
+	}
+}
+
 func (gr *GameHandlers) GetCongratulation() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -5,41 +5,81 @@ import (
 	"log/slog"
 	"ms4me/game/internal/config"
 	gamedto "ms4me/game/internal/http/dto/game"
+	tournamentdto "ms4me/game/internal/http/dto/tournament"
 	"ms4me/game/internal/models"
+	"time"
 )
 
 type GameService interface {
 	CreateGame(ctx context.Context, userID int64, game *gamedto.CreateGameRequest) (string, error)
 	GetGames(ctx context.Context, filter *gamedto.GetGamesRequest) ([]*models.Game, error)
 	GetGame(ctx context.Context, id string) (*models.GameDetails, error)
+	GetGameSettings(ctx context.Context, id string) (*models.GameDetails, error)
 	UpdateGame(ctx context.Context, id string, userID int64, game *gamedto.UpdateGameRequest) error
 	DeleteGame(ctx context.Context, id string, userID int64) error
 	StartGame(ctx context.Context, id string, userID int64) error
 	EnterGame(ctx context.Context, id string, userID int64, username string) error
+	AddBot(ctx context.Context, id string, userID int64, level string) error
 	ExitGame(ctx context.Context, id string, userID int64, username string) error
 	UserGames(ctx context.Context, userID int64) ([]*models.Game, error)
+	UserStats(ctx context.Context, userID int64) (*models.UserStats, error)
 	GetGameStatus(ctx context.Context, gameID string) (string, error)
-	CloseGame(ctx context.Context, gameID string, winnerID int64) error
+	CloseGame(ctx context.Context, gameID string, winnerID int64, reveals map[string]*models.FieldReveal, hints map[string]int, stats map[string]*models.PlayerStats) error
+	SaveCommitment(ctx context.Context, gameID string, userID int64, commitment string) error
+	ArchiveChat(ctx context.Context, gameID string, messages []*models.ChatMessage) error
+	GetChat(ctx context.Context, gameID string) ([]*models.ChatMessage, error)
 	Congratulation(ctx context.Context, gameID string) ([]byte, error)
 }
 
+type TournamentService interface {
+	CreateTournament(ctx context.Context, userID int64, req *tournamentdto.CreateTournamentRequest) (string, error)
+	GetTournaments(ctx context.Context) ([]*models.Tournament, error)
+	GetTournament(ctx context.Context, id string) (*models.TournamentDetails, error)
+	Register(ctx context.Context, id string, userID int64) error
+	Start(ctx context.Context, id string, userID int64) error
+	GameClosed(ctx context.Context, gameID string, winnerID int64) error
+}
+
+type DailyService interface {
+	Challenge(ctx context.Context, userID int64) (*models.DailyChallenge, error)
+	Play(ctx context.Context, userID int64) (string, error)
+	Leaderboard(ctx context.Context, day time.Time) ([]*models.DailyAttempt, error)
+	GameClosed(ctx context.Context, gameID string, winnerID int64, stats map[string]*models.PlayerStats) error
+}
+
+type FriendService interface {
+	Request(ctx context.Context, userID int64, username string) (bool, error)
+	Accept(ctx context.Context, userID, fromID int64) error
+	Remove(ctx context.Context, userID, friendID int64) error
+	List(ctx context.Context, userID int64) ([]*models.Friend, error)
+	SendMessage(ctx context.Context, userID int64, username string, friendID int64, text string) (*models.DirectMessage, error)
+	Messages(ctx context.Context, userID, friendID int64, before int64, limit int) ([]*models.DirectMessage, error)
+	Invite(ctx context.Context, userID int64, username string, friendID int64, gameID string) error
+}
+
 type AuthService interface {
 	Register(ctx context.Context, username, password string) (int64, error)
 	Login(ctx context.Context, username, password string) (string, error)
 }
 
 type GameHandlers struct {
-	log     *slog.Logger
-	gameSrv GameService
-	authSrv AuthService
-	cfg     *config.Config
+	log           *slog.Logger
+	gameSrv       GameService
+	tournamentSrv TournamentService
+	dailySrv      DailyService
+	friendSrv     FriendService
+	authSrv       AuthService
+	cfg           *config.Config
 }
 
-func New(log *slog.Logger, gameSrv GameService, authSrv AuthService, cfg *config.Config) *GameHandlers {
+func New(log *slog.Logger, gameSrv GameService, tournamentSrv TournamentService, dailySrv DailyService, friendSrv FriendService, authSrv AuthService, cfg *config.Config) *GameHandlers {
 	return &GameHandlers{
-		log:     log,
-		gameSrv: gameSrv,
-		authSrv: authSrv,
-		cfg:     cfg,
+		log:           log,
+		gameSrv:       gameSrv,
+		tournamentSrv: tournamentSrv,
+		dailySrv:      dailySrv,
+		friendSrv:     friendSrv,
+		authSrv:       authSrv,
+		cfg:           cfg,
 	}
 }
@@ -37,6 +37,38 @@ func (gh *GameHandlers) GameStatus() http.HandlerFunc {
 	}
 }
 
+// GameSettings отдаёт ingame-srv параметры игры, необходимые для генерации поля
+func (gh *GameHandlers) GameSettings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		w.Header().Add("Content-Type", "application/json")
+
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrEmptyID)
+			return
+		}
+
+		game, err := gh.gameSrv.GetGameSettings(ctx, id)
+		if err != nil {
+			if errors.Is(err, storage.ErrGameNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, response.Error(storage.ErrGameNotFound.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, gamedto.GetGameResponse{
+			Response: response.OK(),
+			Game:     game,
+		})
+	}
+}
+
 func (gh *GameHandlers) CloseGame() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -56,7 +88,7 @@ func (gh *GameHandlers) CloseGame() http.HandlerFunc {
 			return
 		}
 
-		err := gh.gameSrv.CloseGame(ctx, id, req.WinnerID)
+		err := gh.gameSrv.CloseGame(ctx, id, req.WinnerID, req.Reveals, req.Hints, req.Stats)
 		if err != nil {
 			if errors.Is(err, storage.ErrGameNotFound) {
 				w.WriteHeader(http.StatusBadRequest)
@@ -67,7 +99,99 @@ func (gh *GameHandlers) CloseGame() http.HandlerFunc {
 			render.JSON(w, r, response.ErrInternalError)
 			return
 		}
+		// ошибки турниров и испытаний не отменяют закрытие игры, сервисы их уже залогировали
+		_ = gh.tournamentSrv.GameClosed(ctx, id, req.WinnerID)
+		_ = gh.dailySrv.GameClosed(ctx, id, req.WinnerID, req.Stats)
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// SaveCommitment сохраняет commitment поля участника, опубликованную ingame-srv
+func (gh *GameHandlers) SaveCommitment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		w.Header().Add("Content-Type", "application/json")
+
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrEmptyID)
+			return
+		}
+
+		var req gamedto.SaveCommitmentRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, response.ErrBody)
+			return
+		}
+
+		err := gh.gameSrv.SaveCommitment(ctx, id, req.UserID, req.Commitment)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
 
 		render.JSON(w, r, response.OK())
 	}
 }
+
+// ArchiveChat сохраняет историю чата игры, которую ingame-srv выгружает из redis
+func (gh *GameHandlers) ArchiveChat() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		w.Header().Add("Content-Type", "application/json")
+
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrEmptyID)
+			return
+		}
+
+		var req gamedto.ArchiveChatRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, response.ErrBody)
+			return
+		}
+
+		err := gh.gameSrv.ArchiveChat(ctx, id, req.Messages)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+// GetChat отдаёт ingame-srv сохранённую историю чата завершённой игры
+func (gh *GameHandlers) GetChat() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		w.Header().Add("Content-Type", "application/json")
+
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrEmptyID)
+			return
+		}
+
+		messages, err := gh.gameSrv.GetChat(ctx, id)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, gamedto.GetChatResponse{
+			Response: response.OK(),
+			Messages: messages,
+		})
+	}
+}
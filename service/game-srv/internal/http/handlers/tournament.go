@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"ms4me/game/internal/http/dto/response"
+	tournamentdto "ms4me/game/internal/http/dto/tournament"
+	"ms4me/game/internal/http/middlewares"
+	"ms4me/game/internal/services/tournament"
+	"ms4me/game/internal/storage"
+	"ms4me/game/pkg/lib/validator"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+func (gr *GameHandlers) CreateTournament() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		var req tournamentdto.CreateTournamentRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrInvalidBody)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, response.Error(validator.GetDetailedError(err).Error()))
+			return
+		}
+
+		id, err := gr.tournamentSrv.CreateTournament(ctx, user.ID, &req)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, tournamentdto.CreateTournamentResponse{
+			Response: response.OK(),
+			ID:       id,
+		})
+	}
+}
+
+func (gr *GameHandlers) GetTournaments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+
+		tournaments, err := gr.tournamentSrv.GetTournaments(ctx)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, tournamentdto.GetTournamentsResponse{
+			Response:    response.OK(),
+			Tournaments: tournaments,
+		})
+	}
+}
+
+// GetTournament отдаёт состояние турнира: таблицу участников и сетку матчей по раундам
+func (gr *GameHandlers) GetTournament() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrEmptyID)
+			return
+		}
+
+		details, err := gr.tournamentSrv.GetTournament(ctx, id)
+		if err != nil {
+			if errors.Is(err, storage.ErrTournamentNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, response.Error(storage.ErrTournamentNotFound.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, tournamentdto.GetTournamentResponse{
+			Response:   response.OK(),
+			Tournament: details,
+		})
+	}
+}
+
+func (gr *GameHandlers) RegisterTournament() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrEmptyID)
+			return
+		}
+
+		err := gr.tournamentSrv.Register(ctx, id, user.ID)
+		if err != nil {
+			for _, known := range []error{storage.ErrTournamentNotFound, storage.ErrTournamentNotOpen, storage.ErrAlreadyRegistered} {
+				if errors.Is(err, known) {
+					w.WriteHeader(http.StatusBadRequest)
+					render.JSON(w, r, response.Error(known.Error()))
+					return
+				}
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
+
+func (gr *GameHandlers) StartTournament() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrEmptyID)
+			return
+		}
+
+		err := gr.tournamentSrv.Start(ctx, id, user.ID)
+		if err != nil {
+			for _, known := range []error{storage.ErrTournamentNotFound, storage.ErrTournamentNotOpen, tournament.ErrOnlyOrganizerCanStart, tournament.ErrNotEnoughPlayers} {
+				if errors.Is(err, known) {
+					w.WriteHeader(http.StatusBadRequest)
+					render.JSON(w, r, response.Error(known.Error()))
+					return
+				}
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, response.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ChatMessage сообщение чата игры, сохранённое после того, как чат ушёл из redis
+type ChatMessage struct {
+	ID              string     `json:"id"`
+	CreatorID       int64      `json:"creator_id"`
+	CreatorUsername string     `json:"creator_username"`
+	Text            string     `json:"text"`
+	CreatedAt       time.Time  `json:"created_at"`
+	EditedAt        *time.Time `json:"edited_at,omitempty"`
+	Deleted         bool       `json:"deleted,omitempty"` // удалённое автором сообщение убирается из истории
+}
@@ -0,0 +1,23 @@
+package models
+
+// FieldCommitment commitment поля участника игры. Seed, Origin и Layout заполняются после окончания игры
+type FieldCommitment struct {
+	UserID     int64      `json:"user_id"`
+	Commitment string     `json:"commitment"`
+	Seed       *int64     `json:"seed,string,omitempty"`
+	Origin     *CellPoint `json:"origin,omitempty"`
+	Layout     *string    `json:"layout,omitempty"`
+}
+
+// FieldReveal раскрытие поля участника, присылаемое ingame-srv при закрытии игры
+type FieldReveal struct {
+	Seed       int64     `json:"seed,string"`
+	Origin     CellPoint `json:"origin"`
+	Layout     string    `json:"layout"`
+	Commitment string    `json:"commitment"`
+}
+
+type CellPoint struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
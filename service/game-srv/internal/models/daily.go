@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// DailyChallenge ежедневное испытание: одно поле на UTC-день для всех игроков
+type DailyChallenge struct {
+	Day      string        `json:"day"` // дата в формате YYYY-MM-DD
+	Rows     int           `json:"rows"`
+	Cols     int           `json:"cols"`
+	Mines    int           `json:"mines"`
+	Topology string        `json:"topology"`
+	Players  int           `json:"players"`           // сколько игроков начали испытание
+	Winners  int           `json:"winners"`           // сколько игроков прошли поле
+	Attempt  *DailyAttempt `json:"attempt,omitempty"` // попытка текущего пользователя, если он уже играл
+}
+
+// DailyAttempt попытка игрока пройти ежедневное испытание
+type DailyAttempt struct {
+	UserID     int64      `json:"user_id"`
+	Username   string     `json:"username"`
+	GameID     *string    `json:"game_id"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Won        bool       `json:"won"`
+	Moves      int        `json:"moves"`
+	DurationMS *int64     `json:"duration_ms"` // время прохождения, заполняется после окончания игры
+}
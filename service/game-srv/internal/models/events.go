@@ -13,6 +13,25 @@ const (
 	TypeExitGame
 
 	TypeOpenCell
+	TypeLoseGame
+	TypeWinGame
+
+	TypeNewMessage
+
+	TypeDiffGame
+	TypeCommitField
+
+	TypeTournamentRound
+
+	TypeEditMessage
+	TypeDeleteMessage
+
+	TypeLobbyMessage
+
+	TypeEmote
+
+	TypeDirectMessage
+	TypeInvite
 )
 
 type Event struct {
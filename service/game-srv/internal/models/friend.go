@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Friend друг пользователя или заявка в друзья
+type Friend struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Status   string `json:"status"`             // pending или accepted
+	Incoming bool   `json:"incoming,omitempty"` // заявку отправил этот пользователь, принять её может текущий
+	Online   bool   `json:"online"`             // есть активное подключение к ingame-srv
+}
+
+// DirectMessage личное сообщение между друзьями
+type DirectMessage struct {
+	ID             int64     `json:"id"`
+	SenderID       int64     `json:"sender_id"`
+	SenderUsername string    `json:"sender_username"`
+	RecipientID    int64     `json:"recipient_id"`
+	Text           string    `json:"text"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Invite приглашение друга в игру, доставляется ему в лобби
+type Invite struct {
+	GameID         string `json:"game_id"`
+	Title          string `json:"title"`
+	SenderID       int64  `json:"sender_id"`
+	SenderUsername string `json:"sender_username"`
+	RecipientID    int64  `json:"recipient_id"`
+}
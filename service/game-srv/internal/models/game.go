@@ -15,6 +15,15 @@ type Game struct {
 	OwnerID      int64     `json:"owner_id"`
 	OwnerName    string    `json:"owner_name,omitempty"`
 	IsPublic     bool      `json:"is_public"`
+	NoGuess      bool      `json:"no_guess"`
+	Fair         bool      `json:"fair"`
+	Hints        int       `json:"hints"`
+	Topology     string    `json:"topology"`  // соседство клеток: square, torus или hex
+	Questions    bool      `json:"questions"` // можно ли ставить знаки вопроса
+	Scoring      bool      `json:"scoring"`   // победитель определяется по очкам, а не по первому прошедшему поле
+	Lives        int       `json:"lives"`     // сколько мин может открыть участник, последняя заканчивает его игру
+	Coop         bool      `json:"coop"`      // совместная игра на одном общем поле
+	Solo         bool      `json:"solo"`      // одиночная игра, создаётся сразу начатой
 	WinnerID     *int64    `json:"winner_id"`
 	CreatedAt    time.Time `json:"created_at"`
 	Status       string    `json:"status"`
@@ -23,18 +32,31 @@ type Game struct {
 }
 
 type GameDetails struct {
-	ID           string    `json:"id"`
-	Title        string    `json:"title"`
-	Mines        int       `json:"mines"`
-	Rows         int       `json:"rows"`
-	Cols         int       `json:"cols"`
-	OwnerID      int64     `json:"owner_id"`
-	OwnerName    string    `json:"owner_name,omitempty"`
-	IsPublic     bool      `json:"is_public"`
-	CreatedAt    time.Time `json:"created_at"`
-	Status       string    `json:"status"`
-	WinnerID     *int64    `json:"winner_id"`
-	PlayersCount int       `json:"players_count"`
-	MaxPlayers   int       `json:"max_players"`
-	Players      []*User   `json:"players"`
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	Mines        int        `json:"mines"`
+	Rows         int        `json:"rows"`
+	Cols         int        `json:"cols"`
+	OwnerID      int64      `json:"owner_id"`
+	OwnerName    string     `json:"owner_name,omitempty"`
+	IsPublic     bool       `json:"is_public"`
+	NoGuess      bool       `json:"no_guess"`
+	Fair         bool       `json:"fair"`
+	Hints        int        `json:"hints"`
+	Topology     string     `json:"topology"`        // соседство клеток: square, torus или hex
+	Questions    bool       `json:"questions"`       // можно ли ставить знаки вопроса
+	Scoring      bool       `json:"scoring"`         // победитель определяется по очкам, а не по первому прошедшему поле
+	Lives        int        `json:"lives"`           // сколько мин может открыть участник, последняя заканчивает его игру
+	Coop         bool       `json:"coop"`            // совместная игра на одном общем поле
+	Solo         bool       `json:"solo"`            // одиночная игра, создаётся сразу начатой
+	Seed         *int64     `json:"seed,omitempty"`  // сид поля в режиме fair, раскрывается после окончания игры
+	Daily        *time.Time `json:"daily,omitempty"` // день ежедневного испытания, если игра - его попытка
+	CreatedAt    time.Time  `json:"created_at"`
+	Status       string     `json:"status"`
+	WinnerID     *int64     `json:"winner_id"`
+	PlayersCount int        `json:"players_count"`
+	MaxPlayers   int        `json:"max_players"`
+	Players      []*User    `json:"players"`
+
+	Commitments []*FieldCommitment `json:"commitments,omitempty"`
 }
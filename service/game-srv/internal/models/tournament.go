@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// Tournament турнир из нескольких раундов, игры которых создаются автоматически
+type Tournament struct {
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	OrganizerID   int64      `json:"organizer_id"`
+	OrganizerName string     `json:"organizer_name,omitempty"`
+	Format        string     `json:"format"`         // single_elimination или swiss
+	Preset        string     `json:"preset"`         // набор настроек поля для игр турнира
+	RoundMinutes  int        `json:"round_minutes"`  // сколько минут даётся на раунд
+	Rounds        int        `json:"rounds"`         // общее количество раундов, известно после старта
+	CurrentRound  int        `json:"current_round"`  // 0, пока турнир не начат
+	RoundDeadline *time.Time `json:"round_deadline"` // когда незавершённые матчи раунда засчитываются по таймауту
+	Status        string     `json:"status"`
+	WinnerID      *int64     `json:"winner_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	PlayersCount  int        `json:"players_count"`
+}
+
+// TournamentPlayer зарегистрированный участник турнира и его результаты
+type TournamentPlayer struct {
+	ID         int64  `json:"id"`
+	Username   string `json:"username"`
+	Seed       int    `json:"seed"` // номер посева, равен порядку регистрации
+	Wins       int    `json:"wins"`
+	Draws      int    `json:"draws"`
+	Losses     int    `json:"losses"`
+	Byes       int    `json:"byes"` // раунды без соперника, засчитываются как победы
+	Eliminated bool   `json:"eliminated"`
+}
+
+// Points очки участника в швейцарской системе в половинках: победа 2, ничья 1
+func (tp *TournamentPlayer) Points() int {
+	return 2*tp.Wins + tp.Draws
+}
+
+// TournamentMatch матч раунда турнира. Player2ID равен nil, если участник проходит раунд без соперника
+type TournamentMatch struct {
+	ID        int64   `json:"id"`
+	Round     int     `json:"round"`
+	GameID    *string `json:"game_id"`
+	Player1ID int64   `json:"player1_id"`
+	Player2ID *int64  `json:"player2_id"`
+	WinnerID  *int64  `json:"winner_id"` // nil у завершённого матча означает ничью
+	Status    string  `json:"status"`
+}
+
+// TournamentDetails турнир вместе с участниками и сеткой матчей
+type TournamentDetails struct {
+	Tournament
+	Players []*TournamentPlayer `json:"players"`
+	Matches []*TournamentMatch  `json:"matches"`
+}
+
+// TournamentPairing пара участников раунда и созданная для них игра
+type TournamentPairing struct {
+	GameID  string  `json:"game_id"`
+	Players []int64 `json:"players"`
+}
+
+// TournamentRoundEvent событие начала раунда турнира для лобби участников
+type TournamentRoundEvent struct {
+	TournamentID string               `json:"tournament_id"`
+	Title        string               `json:"title"`
+	Round        int                  `json:"round"`
+	Deadline     time.Time            `json:"deadline"`
+	Pairings     []*TournamentPairing `json:"pairings"`
+}
@@ -4,4 +4,25 @@ type User struct {
 	ID       int64  `json:"id"`
 	Username string `json:"username"`
 	Password string `json:"-"`
+
+	HintsUsed   int  `json:"hints_used,omitempty"`   // заполняется только для участников игры
+	Moves       int  `json:"moves,omitempty"`        // ходы участника, заполняется после окончания игры
+	CellsOpened int  `json:"cells_opened,omitempty"` // открытые участником клетки, заполняется после окончания игры
+	IsBot       bool `json:"is_bot,omitempty"`
+}
+
+// PlayerStats итоги участника за игру, присылаемые ingame-srv при закрытии игры
+type PlayerStats struct {
+	Moves       int `json:"moves"`
+	CellsOpened int `json:"cells_opened"`
+}
+
+// UserStats личная статистика пользователя по законченным играм
+type UserStats struct {
+	Played      int `json:"played"`
+	Won         int `json:"won"`
+	SoloPlayed  int `json:"solo_played"`
+	SoloWon     int `json:"solo_won"`
+	Moves       int `json:"moves"`
+	CellsOpened int `json:"cells_opened"`
 }
@@ -0,0 +1,169 @@
+package daily
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"ms4me/game/internal/models"
+	"ms4me/game/internal/storage"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jacute/prettylogger"
+)
+
+// Поле испытания всегда стандартное, как у обычных игр
+const dailyRows = 8
+const dailyCols = 8
+const dailyMines = 10
+const dailyTopology = "square"
+
+// leaderboardLimit сколько лучших результатов отдаёт таблица лидеров
+const leaderboardLimit = 50
+
+type DailyStorage interface {
+	GetDailySeed(ctx context.Context, day time.Time, seed int64) (int64, error)
+	CreateDailyGame(ctx context.Context, game *models.Game, seed int64, day time.Time) error
+	GetDailyStats(ctx context.Context, day time.Time) (int, int, error)
+	GetDailyAttempt(ctx context.Context, day time.Time, userID int64) (*models.DailyAttempt, error)
+	GetDailyAttemptByGame(ctx context.Context, gameID string) (*models.DailyAttempt, error)
+	FinishDailyAttempt(ctx context.Context, gameID string, won bool, moves int) error
+	GetDailyLeaderboard(ctx context.Context, day time.Time, limit int) ([]*models.DailyAttempt, error)
+}
+
+// RoomOpener запускает комнату одиночной игры в ingame-srv
+type RoomOpener interface {
+	OpenSoloRoom(ctx context.Context, id string) error
+}
+
+type Daily struct {
+	log   *slog.Logger
+	DB    DailyStorage
+	rooms RoomOpener
+}
+
+func New(log *slog.Logger, db DailyStorage, rooms RoomOpener) *Daily {
+	return &Daily{log: log, DB: db, rooms: rooms}
+}
+
+// Today возвращает текущий UTC-день, по которому выбирается испытание
+func Today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// Challenge возвращает описание испытания дня и попытку пользователя, если он уже играл
+func (d *Daily) Challenge(ctx context.Context, userID int64) (*models.DailyChallenge, error) {
+	const op = "daily.Challenge"
+	log := d.log.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	day := Today()
+	players, winners, err := d.DB.GetDailyStats(ctx, day)
+	if err != nil {
+		log.Error("error getting daily stats", prettylogger.Err(err))
+		return nil, err
+	}
+	challenge := &models.DailyChallenge{
+		Day:      day.Format(time.DateOnly),
+		Rows:     dailyRows,
+		Cols:     dailyCols,
+		Mines:    dailyMines,
+		Topology: dailyTopology,
+		Players:  players,
+		Winners:  winners,
+	}
+	challenge.Attempt, err = d.DB.GetDailyAttempt(ctx, day, userID)
+	if err != nil && !errors.Is(err, storage.ErrDailyAttemptNotFound) {
+		log.Error("error getting daily attempt", prettylogger.Err(err))
+		return nil, err
+	}
+
+	log.Info("daily challenge got successfully")
+	return challenge, nil
+}
+
+// Play создаёт уже начатую одиночную игру на поле испытания дня. Сыграть испытание можно один раз в день
+func (d *Daily) Play(ctx context.Context, userID int64) (string, error) {
+	const op = "daily.Play"
+	log := d.log.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	day := Today()
+	seed, err := d.DB.GetDailySeed(ctx, day, rand.Int64())
+	if err != nil {
+		log.Error("error getting daily seed", prettylogger.Err(err))
+		return "", err
+	}
+
+	id := uuid.New().String()
+	err = d.DB.CreateDailyGame(ctx, &models.Game{
+		ID:       id,
+		Title:    fmt.Sprintf("Испытание дня %s", day.Format(time.DateOnly)),
+		Mines:    dailyMines,
+		Rows:     dailyRows,
+		Cols:     dailyCols,
+		OwnerID:  userID,
+		IsPublic: false,
+		NoGuess:  true,
+		Fair:     true, // поле строится по сиду дня и совпадает у всех игроков
+		Topology: dailyTopology,
+		Lives:    1,
+	}, seed, day)
+	if err != nil {
+		log.Error("error creating daily game", prettylogger.Err(err))
+		return "", err
+	}
+
+	err = d.rooms.OpenSoloRoom(ctx, id)
+	if err != nil {
+		log.Error("error opening daily game room", prettylogger.Err(err))
+		return "", err
+	}
+
+	log.Info("daily game created successfully", slog.String("game_id", id))
+	return id, nil
+}
+
+// Leaderboard возвращает лучшие результаты испытания дня day
+func (d *Daily) Leaderboard(ctx context.Context, day time.Time) ([]*models.DailyAttempt, error) {
+	const op = "daily.Leaderboard"
+	log := d.log.With(slog.String("op", op), slog.String("day", day.Format(time.DateOnly)))
+
+	attempts, err := d.DB.GetDailyLeaderboard(ctx, day, leaderboardLimit)
+	if err != nil {
+		log.Error("error getting daily leaderboard", prettylogger.Err(err))
+		return nil, err
+	}
+
+	log.Info("daily leaderboard got successfully")
+	return attempts, nil
+}
+
+// GameClosed сохраняет итог попытки испытания. Игры, не относящиеся к испытанию, пропускаются
+func (d *Daily) GameClosed(ctx context.Context, gameID string, winnerID int64, stats map[string]*models.PlayerStats) error {
+	const op = "daily.GameClosed"
+	log := d.log.With(slog.String("op", op), slog.String("game_id", gameID))
+
+	attempt, err := d.DB.GetDailyAttemptByGame(ctx, gameID)
+	if errors.Is(err, storage.ErrDailyAttemptNotFound) {
+		return nil
+	}
+	if err != nil {
+		log.Error("error getting daily attempt", prettylogger.Err(err))
+		return err
+	}
+
+	var moves int
+	if playerStats, ok := stats[strconv.FormatInt(attempt.UserID, 10)]; ok {
+		moves = playerStats.Moves
+	}
+	err = d.DB.FinishDailyAttempt(ctx, gameID, winnerID == attempt.UserID, moves)
+	if err != nil {
+		log.Error("error finishing daily attempt", prettylogger.Err(err))
+		return err
+	}
+
+	log.Info("daily attempt finished successfully", slog.Int64("user_id", attempt.UserID))
+	return nil
+}
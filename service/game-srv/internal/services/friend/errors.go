@@ -0,0 +1,10 @@
+package friend
+
+import "errors"
+
+var (
+	ErrFriendYourself     = errors.New("Нельзя добавить в друзья самого себя")
+	ErrNotFriends         = errors.New("Писать и приглашать в игру можно только друзей")
+	ErrNotGameParticipant = errors.New("Пригласить можно только в игру, в которой ты участвуешь")
+	ErrGameIsNotOpen      = errors.New("Пригласить можно только в ещё не начатую игру")
+)
@@ -0,0 +1,244 @@
+package friend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"ms4me/game/internal/models"
+	"ms4me/game/internal/storage/redis"
+	"slices"
+
+	"github.com/jacute/prettylogger"
+)
+
+const FRIEND_ACCEPTED_STATUS = "accepted"
+const GAME_OPEN_STATUS = "open"
+
+type FriendStorage interface {
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	GetGameByID(ctx context.Context, id string) (*models.GameDetails, error)
+	RequestFriend(ctx context.Context, userID, friendID int64) (bool, error)
+	AcceptFriend(ctx context.Context, userID, fromID int64) error
+	RemoveFriend(ctx context.Context, userID, friendID int64) error
+	GetFriends(ctx context.Context, userID int64) ([]*models.Friend, error)
+	AreFriends(ctx context.Context, userID, friendID int64) (bool, error)
+	CreateDirectMessage(ctx context.Context, message *models.DirectMessage) error
+	GetDirectMessages(ctx context.Context, userID, friendID int64, before int64, limit int) ([]*models.DirectMessage, error)
+}
+
+// Presence узнаёт в ingame-srv, кто из пользователей сейчас подключён
+type Presence interface {
+	Online(ids []int64) ([]int64, error)
+}
+
+type Friends struct {
+	log      *slog.Logger
+	DB       FriendStorage
+	rdb      *redis.Redis
+	presence Presence
+}
+
+func New(log *slog.Logger, db FriendStorage, rdb *redis.Redis, presence Presence) *Friends {
+	return &Friends{log: log, DB: db, rdb: rdb, presence: presence}
+}
+
+// Request отправляет заявку в друзья пользователю username. Если он уже отправил встречную, они становятся друзьями
+func (f *Friends) Request(ctx context.Context, userID int64, username string) (bool, error) {
+	const op = "friend.Request"
+	log := f.log.With(slog.String("op", op), slog.Int64("user_id", userID), slog.String("username", username))
+
+	friend, err := f.DB.GetUserByUsername(ctx, username)
+	if err != nil {
+		log.Info("error getting user", prettylogger.Err(err))
+		return false, err
+	}
+	if friend.ID == userID {
+		return false, fmt.Errorf("%s: %w", op, ErrFriendYourself)
+	}
+	accepted, err := f.DB.RequestFriend(ctx, userID, friend.ID)
+	if err != nil {
+		log.Error("error requesting friend", prettylogger.Err(err))
+		return false, err
+	}
+
+	log.Info("friend request sent successfully", slog.Bool("accepted", accepted))
+	return accepted, nil
+}
+
+// Accept принимает входящую заявку в друзья от fromID. Если заявки нет, возвращается storage.ErrFriendNotFound
+func (f *Friends) Accept(ctx context.Context, userID, fromID int64) error {
+	const op = "friend.Accept"
+	log := f.log.With(slog.String("op", op), slog.Int64("user_id", userID), slog.Int64("from_id", fromID))
+
+	err := f.DB.AcceptFriend(ctx, userID, fromID)
+	if err != nil {
+		log.Error("error accepting friend", prettylogger.Err(err))
+		return err
+	}
+
+	log.Info("friend request accepted successfully")
+	return nil
+}
+
+// Remove удаляет из друзей, отклоняет входящую или отзывает исходящую заявку
+func (f *Friends) Remove(ctx context.Context, userID, friendID int64) error {
+	const op = "friend.Remove"
+	log := f.log.With(slog.String("op", op), slog.Int64("user_id", userID), slog.Int64("friend_id", friendID))
+
+	err := f.DB.RemoveFriend(ctx, userID, friendID)
+	if err != nil {
+		log.Error("error removing friend", prettylogger.Err(err))
+		return err
+	}
+
+	log.Info("friend removed successfully")
+	return nil
+}
+
+// List возвращает друзей и заявки пользователя. Присутствие в сети берётся из ingame-srv,
+// если он недоступен, все считаются не в сети
+func (f *Friends) List(ctx context.Context, userID int64) ([]*models.Friend, error) {
+	const op = "friend.List"
+	log := f.log.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	friends, err := f.DB.GetFriends(ctx, userID)
+	if err != nil {
+		log.Error("error getting friends", prettylogger.Err(err))
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(friends))
+	for _, friend := range friends {
+		if friend.Status == FRIEND_ACCEPTED_STATUS {
+			ids = append(ids, friend.ID)
+		}
+	}
+	online, err := f.presence.Online(ids)
+	if err != nil {
+		log.Warn("error getting friends presence", prettylogger.Err(err))
+	}
+	for _, friend := range friends {
+		friend.Online = slices.Contains(online, friend.ID)
+	}
+
+	log.Info("friends got successfully")
+	return friends, nil
+}
+
+// SendMessage сохраняет личное сообщение другу и доставляет его в лобби получателя
+func (f *Friends) SendMessage(ctx context.Context, userID int64, username string, friendID int64, text string) (*models.DirectMessage, error) {
+	const op = "friend.SendMessage"
+	log := f.log.With(slog.String("op", op), slog.Int64("user_id", userID), slog.Int64("friend_id", friendID))
+
+	err := f.checkFriends(ctx, userID, friendID)
+	if err != nil {
+		log.Info("users are not friends", prettylogger.Err(err))
+		return nil, err
+	}
+
+	message := &models.DirectMessage{
+		SenderID:       userID,
+		SenderUsername: username,
+		RecipientID:    friendID,
+		Text:           text,
+	}
+	err = f.DB.CreateDirectMessage(ctx, message)
+	if err != nil {
+		log.Error("error creating direct message", prettylogger.Err(err))
+		return nil, err
+	}
+	err = f.publish(ctx, models.TypeDirectMessage, userID, username, "", message)
+	if err != nil {
+		log.Error("error pushing direct message event", prettylogger.Err(err))
+		return nil, err
+	}
+
+	log.Info("direct message sent successfully", slog.Int64("message_id", message.ID))
+	return message, nil
+}
+
+// Messages возвращает переписку пользователя с другом friendID, страница заканчивается перед сообщением before.
+// Как и писать, читать переписку можно только пока пользователи друзья
+func (f *Friends) Messages(ctx context.Context, userID, friendID int64, before int64, limit int) ([]*models.DirectMessage, error) {
+	const op = "friend.Messages"
+	log := f.log.With(slog.String("op", op), slog.Int64("user_id", userID), slog.Int64("friend_id", friendID))
+
+	err := f.checkFriends(ctx, userID, friendID)
+	if err != nil {
+		log.Info("users are not friends", prettylogger.Err(err))
+		return nil, err
+	}
+	messages, err := f.DB.GetDirectMessages(ctx, userID, friendID, before, limit)
+	if err != nil {
+		log.Error("error getting direct messages", prettylogger.Err(err))
+		return nil, err
+	}
+
+	log.Info("direct messages got successfully")
+	return messages, nil
+}
+
+// Invite приглашает друга в ещё не начатую игру, в которой участвует пользователь.
+// Приглашение приходит в лобби друга, войти по нему можно и в закрытую игру
+func (f *Friends) Invite(ctx context.Context, userID int64, username string, friendID int64, gameID string) error {
+	const op = "friend.Invite"
+	log := f.log.With(slog.String("op", op), slog.Int64("user_id", userID), slog.Int64("friend_id", friendID), slog.String("game_id", gameID))
+
+	err := f.checkFriends(ctx, userID, friendID)
+	if err != nil {
+		log.Info("users are not friends", prettylogger.Err(err))
+		return err
+	}
+	game, err := f.DB.GetGameByID(ctx, gameID)
+	if err != nil {
+		log.Error("error getting game", prettylogger.Err(err))
+		return err
+	}
+	if !slices.ContainsFunc(game.Players, func(player *models.User) bool { return player.ID == userID }) {
+		return fmt.Errorf("%s: %w", op, ErrNotGameParticipant)
+	}
+	if game.Status != GAME_OPEN_STATUS {
+		return fmt.Errorf("%s: %w", op, ErrGameIsNotOpen)
+	}
+
+	err = f.publish(ctx, models.TypeInvite, userID, username, gameID, &models.Invite{
+		GameID:         gameID,
+		Title:          game.Title,
+		SenderID:       userID,
+		SenderUsername: username,
+		RecipientID:    friendID,
+	})
+	if err != nil {
+		log.Error("error pushing invite event", prettylogger.Err(err))
+		return err
+	}
+
+	log.Info("friend invited successfully")
+	return nil
+}
+
+func (f *Friends) checkFriends(ctx context.Context, userID, friendID int64) error {
+	ok, err := f.DB.AreFriends(ctx, userID, friendID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFriends
+	}
+	return nil
+}
+
+func (f *Friends) publish(ctx context.Context, eventType models.EventType, userID int64, username, gameID string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return f.rdb.PublishEvent(ctx, models.Event{
+		Type:     eventType,
+		UserID:   userID,
+		Username: username,
+		GameID:   gameID,
+		Payload:  data,
+	})
+}
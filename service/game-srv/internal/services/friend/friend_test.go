@@ -0,0 +1,133 @@
+package friend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"ms4me/game/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStorage друзья хранятся парами в обе стороны, методы вне теста не реализованы
+type fakeStorage struct {
+	FriendStorage
+	friends  map[[2]int64]bool
+	games    map[string]*models.GameDetails
+	list     []*models.Friend
+	messages []*models.DirectMessage
+}
+
+func (s *fakeStorage) AreFriends(_ context.Context, userID, friendID int64) (bool, error) {
+	return s.friends[[2]int64{userID, friendID}] || s.friends[[2]int64{friendID, userID}], nil
+}
+
+func (s *fakeStorage) GetGameByID(_ context.Context, id string) (*models.GameDetails, error) {
+	game, ok := s.games[id]
+	if !ok {
+		return nil, errors.New("game not found")
+	}
+	return game, nil
+}
+
+func (s *fakeStorage) GetFriends(context.Context, int64) ([]*models.Friend, error) {
+	return s.list, nil
+}
+
+func (s *fakeStorage) GetDirectMessages(context.Context, int64, int64, int64, int) ([]*models.DirectMessage, error) {
+	return s.messages, nil
+}
+
+type fakePresence struct {
+	online []int64
+	err    error
+}
+
+func (p *fakePresence) Online([]int64) ([]int64, error) {
+	return p.online, p.err
+}
+
+func newFriends(db FriendStorage, presence Presence) *Friends {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), db, nil, presence)
+}
+
+func TestInviteRejected(t *testing.T) {
+	players := []*models.User{{ID: 1}, {ID: 3}}
+	db := &fakeStorage{
+		friends: map[[2]int64]bool{{1, 2}: true, {2, 4}: true},
+		games: map[string]*models.GameDetails{
+			"open":    {ID: "open", Status: GAME_OPEN_STATUS, Players: players},
+			"started": {ID: "started", Status: "started", Players: players},
+		},
+	}
+	friends := newFriends(db, &fakePresence{})
+
+	testCases := []struct {
+		name     string
+		userID   int64
+		friendID int64
+		gameID   string
+		err      error
+	}{
+		{name: "not friends", userID: 1, friendID: 3, gameID: "open", err: ErrNotFriends},
+		{name: "not participant", userID: 4, friendID: 2, gameID: "open", err: ErrNotGameParticipant},
+		{name: "game is not open", userID: 1, friendID: 2, gameID: "started", err: ErrGameIsNotOpen},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := friends.Invite(context.Background(), tc.userID, "user", tc.friendID, tc.gameID)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestListPresence(t *testing.T) {
+	list := func() []*models.Friend {
+		return []*models.Friend{
+			{ID: 2, Status: FRIEND_ACCEPTED_STATUS},
+			{ID: 3, Status: FRIEND_ACCEPTED_STATUS},
+			{ID: 4, Status: "pending"},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		presence *fakePresence
+		online   []bool
+	}{
+		{name: "presence available", presence: &fakePresence{online: []int64{2}}, online: []bool{true, false, false}},
+		{name: "presence failed", presence: &fakePresence{err: errors.New("ingame-srv unavailable")}, online: []bool{false, false, false}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			friends := newFriends(&fakeStorage{list: list()}, tc.presence)
+
+			got, err := friends.List(context.Background(), 1)
+			require.NoError(t, err)
+			online := make([]bool, 0, len(got))
+			for _, friend := range got {
+				online = append(online, friend.Online)
+			}
+			require.Equal(t, tc.online, online)
+		})
+	}
+}
+
+func TestMessagesOnlyFriends(t *testing.T) {
+	db := &fakeStorage{
+		friends:  map[[2]int64]bool{{1, 2}: true},
+		messages: []*models.DirectMessage{{ID: 1, SenderID: 2, RecipientID: 1, Text: "gg"}},
+	}
+	friends := newFriends(db, &fakePresence{})
+
+	messages, err := friends.Messages(context.Background(), 1, 2, 0, 20)
+	require.NoError(t, err)
+	require.Equal(t, db.messages, messages)
+
+	_, err = friends.Messages(context.Background(), 1, 3, 0, 20)
+	require.ErrorIs(t, err, ErrNotFriends)
+}
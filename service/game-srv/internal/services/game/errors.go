@@ -8,4 +8,5 @@ var (
 	ErrGameIsNotOpen         = errors.New("Игра не открыта")
 	ErrGameIsNotClosed       = errors.New("Игра не закончилась")
 	ErrTemplate              = errors.New("Ошибка шаблонизатора")
+	ErrOnlyOwnerCanAddBot    = errors.New("Только создатель может добавлять ботов")
 )
@@ -7,11 +7,15 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	gamedto "ms4me/game/internal/http/dto/game"
 	"ms4me/game/internal/models"
+	"ms4me/game/internal/storage"
 	"ms4me/game/internal/storage/redis"
 	ingameclient "ms4me/game/pkg/ingame_client"
+	"strconv"
 	"text/template"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jacute/prettylogger"
@@ -21,25 +25,39 @@ const defaultGameRows = 8
 const defaultGameCols = 8
 const defaultGameMines = 10
 
+// botUsernamePrefix префикс имён пользователей-ботов, полное имя - префикс и уровень бота
+const botUsernamePrefix = "bot:"
+
 const GAME_STARTED_STATUS = "started"
 const GAME_CLOSED_STATUS = "closed"
 const GAME_OPEN_STATUS = "open"
 
 type GameStorage interface {
 	CreateGame(ctx context.Context, game *models.Game, userID int64) (string, error)
+	CreateSoloGame(ctx context.Context, game *models.Game, seed *int64) error
 	GetGames(ctx context.Context, filter *gamedto.GetGamesRequest) ([]*models.Game, error)
 	GetGameByID(ctx context.Context, id string) (*models.GameDetails, error)
 	GetGameByIDUserID(ctx context.Context, id string, userID int64) (*models.GameDetails, error)
 	UpdateGame(ctx context.Context, id string, userID int64, game *models.Game) error
 	DeleteGame(ctx context.Context, id string, userID int64) error
 	ChangePassword(username, password string) error
-	StartGame(ctx context.Context, id string, userID int64) error
+	StartGame(ctx context.Context, id string, userID int64, seed *int64) error
 	EnterGame(ctx context.Context, id string, userID int64) error
 	ExitGame(ctx context.Context, id string, userID int64) error
 	GetUserGames(ctx context.Context, userID int64) ([]*models.Game, error)
+	GetUserStats(ctx context.Context, userID int64) (*models.UserStats, error)
 	UpdateGameStatus(ctx context.Context, id string, status string) error
 	UpdateWinner(ctx context.Context, id string, winnerID int64) error
 	GetUserByID(ctx context.Context, id int64) (*models.User, error)
+	SaveCommitment(ctx context.Context, gameID string, userID int64, commitment string) error
+	RevealCommitment(ctx context.Context, gameID string, userID int64, reveal *models.FieldReveal) error
+	GetCommitments(ctx context.Context, gameID string) ([]*models.FieldCommitment, error)
+	SaveChatMessages(ctx context.Context, gameID string, messages []*models.ChatMessage) (int, error)
+	GetChatMessages(ctx context.Context, gameID string) ([]*models.ChatMessage, error)
+	UpdateHintsUsed(ctx context.Context, id string, userID int64, hintsUsed int) error
+	UpdatePlayerStats(ctx context.Context, id string, userID int64, stats *models.PlayerStats) error
+	GetBotUser(ctx context.Context, username string) (*models.User, error)
+	AddBotToGame(ctx context.Context, id string, botID int64) error
 }
 
 type Game struct {
@@ -58,13 +76,24 @@ func (g *Game) CreateGame(ctx context.Context, userID int64, game *gamedto.Creat
 	log := g.log.With(slog.String("op", op), slog.Int64("user_id", userID))
 	id := uuid.New().String()
 	newGame := &models.Game{
-		ID:       id,
-		Title:    game.Title,
-		Mines:    defaultGameMines,
-		Rows:     defaultGameRows,
-		Cols:     defaultGameCols,
-		OwnerID:  userID,
-		IsPublic: *game.IsPublic,
+		ID:        id,
+		Title:     game.Title,
+		Mines:     defaultGameMines,
+		Rows:      defaultGameRows,
+		Cols:      defaultGameCols,
+		OwnerID:   userID,
+		IsPublic:  *game.IsPublic,
+		NoGuess:   game.NoGuess,
+		Fair:      game.Fair,
+		Hints:     *game.Hints,
+		Topology:  game.Topology,
+		Questions: game.Questions,
+		Scoring:   game.Scoring,
+		Lives:     game.Lives,
+		Coop:      game.Coop,
+	}
+	if game.Solo {
+		return g.createSoloGame(ctx, log, newGame)
 	}
 
 	_, err := g.DB.CreateGame(ctx, newGame, userID)
@@ -99,6 +128,66 @@ func (g *Game) CreateGame(ctx context.Context, userID int64, game *gamedto.Creat
 	return id, nil
 }
 
+// createSoloGame создаёт закрытую одиночную игру, которая начинается сразу, без лобби и проверки готовности
+func (g *Game) createSoloGame(ctx context.Context, log *slog.Logger, game *models.Game) (string, error) {
+	game.IsPublic = false
+	var seed *int64
+	if game.Fair {
+		value := rand.Int64()
+		seed = &value
+	}
+	err := g.DB.CreateSoloGame(ctx, game, seed)
+	if err != nil {
+		log.Error("error creating solo game", prettylogger.Err(err))
+		return "", err
+	}
+	err = g.OpenSoloRoom(ctx, game.ID)
+	if err != nil {
+		log.Error("error opening solo game room", prettylogger.Err(err))
+		return "", err
+	}
+
+	log.Info("solo game created successfully", slog.String("game_id", game.ID))
+
+	return game.ID, nil
+}
+
+// OpenSoloRoom создаёт в ingame-srv комнату уже начатой одиночной игры и сразу запускает её
+func (g *Game) OpenSoloRoom(ctx context.Context, id string) error {
+	const op = "game.OpenSoloRoom"
+	log := g.log.With(slog.String("op", op), slog.String("game_id", id))
+
+	game, err := g.DB.GetGameByID(ctx, id)
+	if err != nil {
+		log.Error("error got game", prettylogger.Err(err))
+		return err
+	}
+	game.Seed = nil // событие создания уходит в лобби, сид раскрывается только после игры
+	gameMarshalled, err := json.Marshal(game)
+	if err != nil {
+		log.Error("error marshalling game", prettylogger.Err(err))
+		return err
+	}
+	if err = g.rdb.PublishEvents(ctx, []models.Event{
+		{
+			Type:     models.TypeCreateGame,
+			GameID:   id,
+			UserID:   game.OwnerID,
+			Username: game.OwnerName,
+			Payload:  gameMarshalled,
+		},
+		{
+			Type:   models.TypeStartGame,
+			GameID: id,
+			UserID: game.OwnerID,
+		},
+	}); err != nil {
+		log.Error("error pushing solo game events", prettylogger.Err(err))
+		return err
+	}
+	return nil
+}
+
 func (g *Game) GetGames(ctx context.Context, filter *gamedto.GetGamesRequest) ([]*models.Game, error) {
 	const op = "game.GetGames"
 	log := g.log.With(slog.String("op", op))
@@ -121,12 +210,46 @@ func (g *Game) GetGame(ctx context.Context, id string) (*models.GameDetails, err
 		log.Error("error getting game", prettylogger.Err(err))
 		return nil, err
 	}
+	if game.Status != GAME_CLOSED_STATUS || dailyRunning(game) {
+		game.Seed = nil // сид раскрывается только после окончания игры, иначе по нему можно восстановить расположение мин
+	}
+	game.Commitments, err = g.DB.GetCommitments(ctx, id)
+	if err != nil {
+		log.Error("error getting field commitments", prettylogger.Err(err))
+		return nil, err
+	}
+	if dailyRunning(game) {
+		// Поле испытания общее на весь день, раскрытие покажется только когда его уже нельзя сыграть
+		for _, commitment := range game.Commitments {
+			commitment.Seed, commitment.Origin, commitment.Layout = nil, nil, nil
+		}
+	}
 
 	log.Info("game got successfully")
 
 	return game, nil
 }
 
+// dailyRunning проверяет, что игра - попытка ежедневного испытания, которое ещё идёт
+func dailyRunning(game *models.GameDetails) bool {
+	return game.Daily != nil && time.Now().UTC().Before(game.Daily.AddDate(0, 0, 1))
+}
+
+// GetGameSettings возвращает игру вместе с сидом поля для ingame-srv
+func (g *Game) GetGameSettings(ctx context.Context, id string) (*models.GameDetails, error) {
+	const op = "game.GetGameSettings"
+	log := g.log.With(slog.String("op", op), slog.String("game_id", id))
+	game, err := g.DB.GetGameByID(ctx, id)
+	if err != nil {
+		log.Error("error getting game", prettylogger.Err(err))
+		return nil, err
+	}
+
+	log.Info("game settings got successfully")
+
+	return game, nil
+}
+
 func (g *Game) UpdateGame(ctx context.Context, id string, userID int64, game *gamedto.UpdateGameRequest) error {
 	const op = "game.UpdateGame"
 	log := g.log.With(slog.String("op", op), slog.String("id", id), slog.Int64("user_id", userID))
@@ -138,8 +261,20 @@ func (g *Game) UpdateGame(ctx context.Context, id string, userID int64, game *ga
 		IsPublic: *game.IsPublic,
 	}
 	gameBeforeUpdate, err := g.DB.GetGameByID(ctx, id)
+	if errors.Is(err, storage.ErrGameNotFound) {
+		return fmt.Errorf("%s: %w", op, storage.ErrGameNotFoundOrNotYourOwn)
+	}
 	if err != nil {
 		log.Error("error got game", prettylogger.Err(err))
+		return err
+	}
+	if gameBeforeUpdate.OwnerID != userID {
+		return fmt.Errorf("%s: %w", op, storage.ErrGameNotFoundOrNotYourOwn)
+	}
+	// После старта у игры уже есть сид, а изменения настроек начатой игры всё равно не применились бы
+	if gameBeforeUpdate.Status != GAME_OPEN_STATUS {
+		log.Info("game is not open")
+		return fmt.Errorf("%s: %w", op, ErrGameIsNotOpen)
 	}
 	err = g.DB.UpdateGame(ctx, id, userID, newGame)
 	if err != nil {
@@ -149,7 +284,9 @@ func (g *Game) UpdateGame(ctx context.Context, id string, userID int64, game *ga
 	gameAfterUpdate, err := g.DB.GetGameByID(ctx, id)
 	if err != nil {
 		log.Error("error got game", prettylogger.Err(err))
+		return err
 	}
+	gameAfterUpdate.Seed = nil // событие уходит в лобби, сид раскрывается только после игры
 	gameMarshalled, err := json.Marshal(gameAfterUpdate)
 	if err != nil {
 		log.Error("error marshalling game", prettylogger.Err(err))
@@ -224,14 +361,42 @@ func (g *Game) StartGame(ctx context.Context, id string, userID int64) error {
 		log.Info("game is not open")
 		return fmt.Errorf("%s: %w", op, ErrGameIsNotOpen)
 	}
-	err = g.DB.StartGame(ctx, id, userID)
+	return g.startGame(ctx, log, game, userID)
+}
+
+// StartMatchGame начинает игру матча турнира сразу после создания, не дожидаясь подключения участников.
+// Раунд турнира ограничен по времени, поэтому ждать, пока владелец нажмёт старт, нельзя
+func (g *Game) StartMatchGame(ctx context.Context, id string, userID int64) error {
+	const op = "game.StartMatchGame"
+	log := g.log.With(slog.String("op", op), slog.String("game_id", id), slog.Int64("user_id", userID))
+
+	game, err := g.DB.GetGameByID(ctx, id)
+	if err != nil {
+		log.Error("error getting game", prettylogger.Err(err))
+		return err
+	}
+	if game.Status != GAME_OPEN_STATUS {
+		log.Info("game is not open")
+		return fmt.Errorf("%s: %w", op, ErrGameIsNotOpen)
+	}
+	return g.startGame(ctx, log, game, userID)
+}
+
+// startGame задаёт сид поля, переводит игру в статус started и сообщает об этом ingame-srv
+func (g *Game) startGame(ctx context.Context, log *slog.Logger, game *models.GameDetails, userID int64) error {
+	var seed *int64
+	if game.Fair {
+		value := rand.Int64()
+		seed = &value
+	}
+	err := g.DB.StartGame(ctx, game.ID, userID, seed)
 	if err != nil {
 		log.Error("error starting game", prettylogger.Err(err))
 		return err
 	}
 	if err = g.rdb.PublishEvent(ctx, models.Event{
 		Type:     models.TypeStartGame,
-		GameID:   id,
+		GameID:   game.ID,
 		IsPublic: game.IsPublic,
 		UserID:   userID,
 	}); err != nil {
@@ -273,6 +438,53 @@ func (g *Game) EnterGame(ctx context.Context, id string, userID int64, username
 	return nil
 }
 
+// AddBot добавляет в открытую игру бота указанного уровня. Добавлять ботов может только создатель игры
+func (g *Game) AddBot(ctx context.Context, id string, userID int64, level string) error {
+	const op = "game.AddBot"
+	log := g.log.With(slog.String("op", op), slog.String("game_id", id), slog.Int64("user_id", userID), slog.String("level", level))
+	game, err := g.DB.GetGameByID(ctx, id)
+	if err != nil {
+		log.Error("error getting game", prettylogger.Err(err))
+		return err
+	}
+	if game.OwnerID != userID {
+		log.Info("only owner can add bots")
+		return fmt.Errorf("%s: %w", op, ErrOnlyOwnerCanAddBot)
+	}
+	if game.Status != GAME_OPEN_STATUS {
+		log.Info("game is not open")
+		return fmt.Errorf("%s: %w", op, ErrGameIsNotOpen)
+	}
+	bot, err := g.DB.GetBotUser(ctx, botUsernamePrefix+level)
+	if err != nil {
+		log.Error("error getting bot user", prettylogger.Err(err))
+		return err
+	}
+	err = g.DB.AddBotToGame(ctx, id, bot.ID)
+	if err != nil {
+		log.Error("error adding bot to game", prettylogger.Err(err))
+		return err
+	}
+	payload, err := json.Marshal(map[string]string{"bot": level})
+	if err != nil {
+		log.Error("error marshalling payload", prettylogger.Err(err))
+		return err
+	}
+	if err = g.rdb.PublishEvent(ctx, models.Event{
+		Type:     models.TypeJoinGame,
+		GameID:   id,
+		UserID:   bot.ID,
+		IsPublic: game.IsPublic,
+		Username: bot.Username,
+		Payload:  payload,
+	}); err != nil {
+		log.Error("error pushing event", slog.String("event_type", "enter_game"), prettylogger.Err(err))
+		return err
+	}
+	log.Info("bot added to game successfully")
+	return nil
+}
+
 func (g *Game) ExitGame(ctx context.Context, id string, userID int64, username string) error {
 	const op = "game.ExitGame"
 	log := g.log.With(slog.String("op", op), slog.String("game_id", id), slog.Int64("user_id", userID))
@@ -312,6 +524,19 @@ func (g *Game) UserGames(ctx context.Context, userID int64) ([]*models.Game, err
 	return games, nil
 }
 
+// UserStats возвращает личную статистику пользователя по законченным играм, включая одиночные
+func (g *Game) UserStats(ctx context.Context, userID int64) (*models.UserStats, error) {
+	const op = "game.UserStats"
+	log := g.log.With(slog.String("op", op), slog.Int64("user_id", userID))
+	stats, err := g.DB.GetUserStats(ctx, userID)
+	if err != nil {
+		log.Error("error got user stats", prettylogger.Err(err))
+		return nil, err
+	}
+	log.Info("user stats got successfully")
+	return stats, nil
+}
+
 func (g *Game) GetGameStatus(ctx context.Context, gameID string) (string, error) {
 	const op = "game.GetGameStatus"
 	log := g.log.With(slog.String("op", op), slog.String("game_id", gameID))
@@ -325,7 +550,8 @@ func (g *Game) GetGameStatus(ctx context.Context, gameID string) (string, error)
 	return game.Status, nil
 }
 
-func (g *Game) CloseGame(ctx context.Context, gameID string, winnerID int64) error {
+// CloseGame закрывает игру и сохраняет её итоги. winnerID равен 0, если победителя нет, например при проигрыше в совместной игре
+func (g *Game) CloseGame(ctx context.Context, gameID string, winnerID int64, reveals map[string]*models.FieldReveal, hints map[string]int, stats map[string]*models.PlayerStats) error {
 	const op = "game.CloseGame"
 	log := g.log.With(slog.String("op", op), slog.String("game_id", gameID))
 
@@ -334,15 +560,95 @@ func (g *Game) CloseGame(ctx context.Context, gameID string, winnerID int64) err
 		log.Error("error closing game", prettylogger.Err(err))
 		return err
 	}
-	err = g.DB.UpdateWinner(ctx, gameID, winnerID)
+	if winnerID != 0 {
+		err = g.DB.UpdateWinner(ctx, gameID, winnerID)
+		if err != nil {
+			log.Error("error updating winner of game", prettylogger.Err(err))
+			return err
+		}
+	}
+	for key, reveal := range reveals {
+		userID, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			log.Warn("invalid user id in field reveal", slog.String("user_id", key))
+			continue
+		}
+		err = g.DB.RevealCommitment(ctx, gameID, userID, reveal)
+		if err != nil {
+			log.Error("error revealing field commitment", prettylogger.Err(err))
+			return err
+		}
+	}
+	for key, hintsUsed := range hints {
+		userID, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			log.Warn("invalid user id in hints", slog.String("user_id", key))
+			continue
+		}
+		err = g.DB.UpdateHintsUsed(ctx, gameID, userID, hintsUsed)
+		if err != nil {
+			log.Error("error updating hints used", prettylogger.Err(err))
+			return err
+		}
+	}
+	for key, playerStats := range stats {
+		userID, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			log.Warn("invalid user id in player stats", slog.String("user_id", key))
+			continue
+		}
+		err = g.DB.UpdatePlayerStats(ctx, gameID, userID, playerStats)
+		if err != nil {
+			log.Error("error updating player stats", prettylogger.Err(err))
+			return err
+		}
+	}
+	log.Info("game closed successfully")
+	return nil
+}
+
+// SaveCommitment сохраняет commitment поля участника, чтобы её можно было проверить по истории игры
+func (g *Game) SaveCommitment(ctx context.Context, gameID string, userID int64, commitment string) error {
+	const op = "game.SaveCommitment"
+	log := g.log.With(slog.String("op", op), slog.String("game_id", gameID), slog.Int64("user_id", userID))
+
+	err := g.DB.SaveCommitment(ctx, gameID, userID, commitment)
 	if err != nil {
-		log.Error("error updating winner of game", prettylogger.Err(err))
+		log.Error("error saving field commitment", prettylogger.Err(err))
 		return err
 	}
-	log.Info("game closed successfully")
+	log.Info("field commitment saved successfully")
+	return nil
+}
+
+// ArchiveChat сохраняет сообщения чата игры, присланные ingame-srv, чтобы история пережила ttl чата в redis
+func (g *Game) ArchiveChat(ctx context.Context, gameID string, messages []*models.ChatMessage) error {
+	const op = "game.ArchiveChat"
+	log := g.log.With(slog.String("op", op), slog.String("game_id", gameID))
+
+	saved, err := g.DB.SaveChatMessages(ctx, gameID, messages)
+	if err != nil {
+		log.Error("error saving chat messages", prettylogger.Err(err))
+		return err
+	}
+	log.Info("chat archived successfully", slog.Int("count", saved))
 	return nil
 }
 
+// GetChat возвращает сохранённую историю чата игры
+func (g *Game) GetChat(ctx context.Context, gameID string) ([]*models.ChatMessage, error) {
+	const op = "game.GetChat"
+	log := g.log.With(slog.String("op", op), slog.String("game_id", gameID))
+
+	messages, err := g.DB.GetChatMessages(ctx, gameID)
+	if err != nil {
+		log.Error("error getting chat messages", prettylogger.Err(err))
+		return nil, err
+	}
+	log.Info("chat got successfully")
+	return messages, nil
+}
+
 func (h *Game) Congratulation(ctx context.Context, gameID string) ([]byte, error) {
 	const op = "game.Congratulation"
 	log := h.log.With(slog.String("op", op), slog.String("game_id", gameID))
@@ -0,0 +1,182 @@
+package tournament
+
+import (
+	"math/bits"
+	"ms4me/game/internal/models"
+	"slices"
+)
+
+const FORMAT_SINGLE_ELIMINATION = "single_elimination"
+const FORMAT_SWISS = "swiss"
+
+// roundsCount количество раундов турнира: в олимпийской системе столько нужно, чтобы остался один участник,
+// в швейцарской столько же, чтобы лидер определился однозначно хотя бы при отсутствии ничьих
+func roundsCount(players int) int {
+	if players < 2 {
+		return 1
+	}
+	return bits.Len(uint(players - 1))
+}
+
+// pairing пара участников раунда, первым идёт участник с лучшим посевом
+type pairing struct {
+	player1 int64
+	player2 int64
+}
+
+// pairRound строит пары очередного раунда. Второе значение - участник без соперника или 0
+func pairRound(format string, players []*models.TournamentPlayer, matches []*models.TournamentMatch, round int) ([]pairing, int64) {
+	if format == FORMAT_SWISS {
+		return pairSwiss(players, matches)
+	}
+	return pairElimination(players, matches, round)
+}
+
+// pairElimination строит пары олимпийской системы. В первом раунде сильнейший посев играет со слабейшим,
+// дальше победители соседних матчей встречаются между собой. Проход без соперника получает лучший посев
+func pairElimination(players []*models.TournamentPlayer, matches []*models.TournamentMatch, round int) ([]pairing, int64) {
+	seeds := make(map[int64]int, len(players))
+	for _, player := range players {
+		seeds[player.ID] = player.Seed
+	}
+
+	var order []int64
+	if round == 1 {
+		sorted := slices.Clone(players)
+		slices.SortFunc(sorted, func(a, b *models.TournamentPlayer) int { return a.Seed - b.Seed })
+		for i, j := 0, len(sorted)-1; i <= j; i, j = i+1, j-1 {
+			order = append(order, sorted[i].ID)
+			if i != j {
+				order = append(order, sorted[j].ID)
+			}
+		}
+	} else {
+		previous := make([]*models.TournamentMatch, 0)
+		for _, match := range matches {
+			if match.Round == round-1 && match.WinnerID != nil {
+				previous = append(previous, match)
+			}
+		}
+		slices.SortFunc(previous, func(a, b *models.TournamentMatch) int { return int(a.ID - b.ID) })
+		for _, match := range previous {
+			order = append(order, *match.WinnerID)
+		}
+	}
+
+	var bye int64
+	if len(order)%2 == 1 {
+		best := 0
+		for i, id := range order {
+			if seeds[id] < seeds[order[best]] {
+				best = i
+			}
+		}
+		bye = order[best]
+		order = slices.Delete(order, best, best+1)
+	}
+
+	pairs := make([]pairing, 0, len(order)/2)
+	for i := 0; i+1 < len(order); i += 2 {
+		pairs = append(pairs, orderedPairing(order[i], order[i+1], seeds))
+	}
+	return pairs, bye
+}
+
+// pairSwiss строит пары швейцарской системы: участники с близкими очками играют между собой,
+// повторных встреч по возможности нет. Проход без соперника получает худший в таблице, у кого его ещё не было
+func pairSwiss(players []*models.TournamentPlayer, matches []*models.TournamentMatch) ([]pairing, int64) {
+	seeds := make(map[int64]int, len(players))
+	for _, player := range players {
+		seeds[player.ID] = player.Seed
+	}
+	played := make(map[pairing]bool)
+	for _, match := range matches {
+		if match.Player2ID != nil {
+			played[orderedPairing(match.Player1ID, *match.Player2ID, seeds)] = true
+		}
+	}
+
+	standings := slices.Clone(players)
+	sortStandings(standings)
+
+	var bye int64
+	if len(standings)%2 == 1 {
+		candidate := len(standings) - 1
+		for i := len(standings) - 1; i >= 0; i-- {
+			if standings[i].Byes == 0 {
+				candidate = i
+				break
+			}
+		}
+		bye = standings[candidate].ID
+		standings = slices.Delete(standings, candidate, candidate+1)
+	}
+
+	paired := make([]bool, len(standings))
+	pairs := make([]pairing, 0, len(standings)/2)
+	for i := range standings {
+		if paired[i] {
+			continue
+		}
+		opponent := -1
+		for j := i + 1; j < len(standings); j++ {
+			if paired[j] {
+				continue
+			}
+			if opponent == -1 {
+				opponent = j // если все оставшиеся уже встречались, играет с ближайшим по таблице
+			}
+			if !played[orderedPairing(standings[i].ID, standings[j].ID, seeds)] {
+				opponent = j
+				break
+			}
+		}
+		if opponent == -1 {
+			break
+		}
+		paired[i], paired[opponent] = true, true
+		pairs = append(pairs, orderedPairing(standings[i].ID, standings[opponent].ID, seeds))
+	}
+	return pairs, bye
+}
+
+// sortStandings сортирует участников по очкам, при равенстве выше лучший посев
+func sortStandings(players []*models.TournamentPlayer) {
+	slices.SortStableFunc(players, func(a, b *models.TournamentPlayer) int {
+		if a.Points() != b.Points() {
+			return b.Points() - a.Points()
+		}
+		return a.Seed - b.Seed
+	})
+}
+
+func orderedPairing(a, b int64, seeds map[int64]int) pairing {
+	if seeds[b] < seeds[a] {
+		a, b = b, a
+	}
+	return pairing{player1: a, player2: b}
+}
+
+// tournamentWinner возвращает победителя, если турнир после раунда round закончен, иначе 0
+func tournamentWinner(format string, players []*models.TournamentPlayer, round, rounds int) int64 {
+	if format == FORMAT_SWISS {
+		if round < rounds {
+			return 0
+		}
+		standings := slices.Clone(players)
+		sortStandings(standings)
+		return standings[0].ID
+	}
+
+	var winner int64
+	for _, player := range players {
+		if player.Eliminated {
+			continue
+		}
+		if winner != 0 {
+			return 0
+		}
+		winner = player.ID
+	}
+	return winner
+}
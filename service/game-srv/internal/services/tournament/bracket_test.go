@@ -0,0 +1,293 @@
+package tournament
+
+import (
+	"ms4me/game/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// seeded создаёт участников с id от 1 до n, посев равен id
+func seeded(n int) []*models.TournamentPlayer {
+	players := make([]*models.TournamentPlayer, 0, n)
+	for i := 1; i <= n; i++ {
+		players = append(players, &models.TournamentPlayer{ID: int64(i), Seed: i})
+	}
+	return players
+}
+
+// played создаёт завершённый матч раунда. Нулевой player2 означает проход без соперника, нулевой winner - ничью
+func played(id int64, round int, player1, player2, winner int64) *models.TournamentMatch {
+	match := &models.TournamentMatch{ID: id, Round: round, Player1ID: player1}
+	if player2 != 0 {
+		match.Player2ID = &player2
+	}
+	if winner != 0 {
+		match.WinnerID = &winner
+	}
+	return match
+}
+
+func TestRoundsCount(t *testing.T) {
+	testCases := []struct {
+		players int
+		want    int
+	}{
+		{players: 0, want: 1},
+		{players: 1, want: 1},
+		{players: 2, want: 1},
+		{players: 3, want: 2},
+		{players: 4, want: 2},
+		{players: 5, want: 3},
+		{players: 8, want: 3},
+		{players: 9, want: 4},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, roundsCount(tc.players), "players: %d", tc.players)
+	}
+}
+
+func TestPairElimination(t *testing.T) {
+	testCases := []struct {
+		name    string
+		players []*models.TournamentPlayer
+		matches []*models.TournamentMatch
+		round   int
+		want    []pairing
+		bye     int64
+	}{
+		{
+			name:    "even",
+			players: seeded(4),
+			round:   1,
+			want:    []pairing{{1, 4}, {2, 3}},
+		},
+		{
+			name:    "registration order does not matter",
+			players: []*models.TournamentPlayer{{ID: 30, Seed: 3}, {ID: 10, Seed: 1}, {ID: 40, Seed: 4}, {ID: 20, Seed: 2}},
+			round:   1,
+			want:    []pairing{{10, 40}, {20, 30}},
+		},
+		{
+			name:    "three players",
+			players: seeded(3),
+			round:   1,
+			want:    []pairing{{2, 3}},
+			bye:     1,
+		},
+		{
+			name:    "five players",
+			players: seeded(5),
+			round:   1,
+			want:    []pairing{{2, 5}, {3, 4}},
+			bye:     1,
+		},
+		{
+			name:    "winners of adjacent matches meet",
+			players: seeded(8),
+			matches: []*models.TournamentMatch{
+				played(3, 1, 3, 6, 3), played(1, 1, 1, 8, 1), played(4, 1, 4, 5, 4), played(2, 1, 2, 7, 7),
+			},
+			round: 2,
+			want:  []pairing{{1, 7}, {3, 4}},
+		},
+		{
+			name:    "bye goes to the best seed again in the second round",
+			players: seeded(5),
+			matches: []*models.TournamentMatch{
+				played(1, 1, 2, 5, 5), played(2, 1, 3, 4, 3), played(3, 1, 1, 0, 1),
+			},
+			round: 2,
+			want:  []pairing{{3, 5}},
+			bye:   1,
+		},
+		{
+			name:    "bye match winner plays the next round in match order",
+			players: seeded(5),
+			matches: []*models.TournamentMatch{
+				played(1, 1, 2, 5, 5), played(2, 1, 3, 4, 3), played(3, 1, 1, 0, 1),
+				played(4, 2, 3, 5, 3), played(5, 2, 1, 0, 1),
+			},
+			round: 3,
+			want:  []pairing{{1, 3}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pairs, bye := pairElimination(tc.players, tc.matches, tc.round)
+			require.Equal(t, tc.want, pairs)
+			require.Equal(t, tc.bye, bye)
+		})
+	}
+}
+
+func TestPairSwiss(t *testing.T) {
+	testCases := []struct {
+		name    string
+		players func() []*models.TournamentPlayer
+		matches []*models.TournamentMatch
+		want    []pairing
+		bye     int64
+	}{
+		{
+			name:    "first round by seed",
+			players: func() []*models.TournamentPlayer { return seeded(4) },
+			want:    []pairing{{1, 2}, {3, 4}},
+		},
+		{
+			name: "by points",
+			players: func() []*models.TournamentPlayer {
+				players := seeded(4)
+				players[2].Wins, players[3].Wins = 1, 1
+				return players
+			},
+			matches: []*models.TournamentMatch{played(1, 1, 1, 4, 4), played(2, 1, 2, 3, 3)},
+			want:    []pairing{{3, 4}, {1, 2}},
+		},
+		{
+			name: "no rematch",
+			players: func() []*models.TournamentPlayer {
+				players := seeded(4)
+				for _, player := range players {
+					player.Draws = 1
+				}
+				return players
+			},
+			matches: []*models.TournamentMatch{played(1, 1, 1, 2, 0), played(2, 1, 3, 4, 0)},
+			want:    []pairing{{1, 3}, {2, 4}},
+		},
+		{
+			name:    "rematch with the nearest when the leader has played everyone",
+			players: func() []*models.TournamentPlayer { return seeded(4) },
+			matches: []*models.TournamentMatch{played(1, 1, 1, 2, 0), played(2, 2, 1, 3, 0), played(3, 3, 1, 4, 0)},
+			want:    []pairing{{1, 2}, {3, 4}},
+		},
+		{
+			name:    "every remaining pair has already played",
+			players: func() []*models.TournamentPlayer { return seeded(4) },
+			matches: []*models.TournamentMatch{
+				played(1, 1, 1, 2, 0), played(2, 1, 3, 4, 0),
+				played(3, 2, 1, 3, 0), played(4, 2, 2, 4, 0),
+				played(5, 3, 1, 4, 0), played(6, 3, 2, 3, 0),
+			},
+			want: []pairing{{1, 2}, {3, 4}},
+		},
+		{
+			name:    "bye to the last in standings",
+			players: func() []*models.TournamentPlayer { return seeded(5) },
+			want:    []pairing{{1, 2}, {3, 4}},
+			bye:     5,
+		},
+		{
+			name: "bye skips players who already had one",
+			players: func() []*models.TournamentPlayer {
+				players := seeded(5)
+				players[0].Wins, players[1].Wins = 1, 1
+				players[4].Wins, players[4].Byes = 1, 1
+				return players
+			},
+			matches: []*models.TournamentMatch{played(1, 1, 1, 3, 1), played(2, 1, 2, 4, 2), played(3, 1, 5, 0, 5)},
+			want:    []pairing{{1, 2}, {3, 5}},
+			bye:     4,
+		},
+		{
+			name: "bye to the last when everyone had one",
+			players: func() []*models.TournamentPlayer {
+				players := seeded(3)
+				for _, player := range players {
+					player.Wins, player.Byes = 1, 1
+				}
+				return players
+			},
+			want: []pairing{{1, 2}},
+			bye:  3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pairs, bye := pairSwiss(tc.players(), tc.matches)
+			require.Equal(t, tc.want, pairs)
+			require.Equal(t, tc.bye, bye)
+		})
+	}
+}
+
+func TestTournamentWinner(t *testing.T) {
+	testCases := []struct {
+		name    string
+		format  string
+		players func() []*models.TournamentPlayer
+		round   int
+		rounds  int
+		want    int64
+	}{
+		{
+			name:   "elimination in progress",
+			format: FORMAT_SINGLE_ELIMINATION,
+			players: func() []*models.TournamentPlayer {
+				players := seeded(4)
+				players[1].Eliminated, players[2].Eliminated = true, true
+				return players
+			},
+			round:  1,
+			rounds: 2,
+		},
+		{
+			name:   "elimination finished",
+			format: FORMAT_SINGLE_ELIMINATION,
+			players: func() []*models.TournamentPlayer {
+				players := seeded(4)
+				players[0].Eliminated, players[1].Eliminated, players[2].Eliminated = true, true, true
+				return players
+			},
+			round:  2,
+			rounds: 2,
+			want:   4,
+		},
+		{
+			name:   "swiss before the last round",
+			format: FORMAT_SWISS,
+			players: func() []*models.TournamentPlayer {
+				players := seeded(4)
+				players[3].Wins = 1
+				return players
+			},
+			round:  1,
+			rounds: 2,
+		},
+		{
+			name:   "swiss leader by points",
+			format: FORMAT_SWISS,
+			players: func() []*models.TournamentPlayer {
+				players := seeded(4)
+				players[0].Wins, players[0].Losses = 1, 1
+				players[3].Wins, players[3].Draws = 1, 1
+				return players
+			},
+			round:  2,
+			rounds: 2,
+			want:   4,
+		},
+		{
+			name:   "swiss tie goes to the better seed",
+			format: FORMAT_SWISS,
+			players: func() []*models.TournamentPlayer {
+				players := seeded(4)
+				players[1].Wins, players[2].Wins = 2, 2
+				return players
+			},
+			round:  2,
+			rounds: 2,
+			want:   2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tournamentWinner(tc.format, tc.players(), tc.round, tc.rounds))
+		})
+	}
+}
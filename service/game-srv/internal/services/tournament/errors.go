@@ -0,0 +1,8 @@
+package tournament
+
+import "errors"
+
+var (
+	ErrOnlyOrganizerCanStart = errors.New("Только организатор может начать турнир")
+	ErrNotEnoughPlayers      = errors.New("Для турнира нужно хотя бы два участника")
+)
@@ -0,0 +1,449 @@
+package tournament
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	gamedto "ms4me/game/internal/http/dto/game"
+	tournamentdto "ms4me/game/internal/http/dto/tournament"
+	"ms4me/game/internal/models"
+	"ms4me/game/internal/storage"
+	"ms4me/game/internal/storage/redis"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jacute/prettylogger"
+)
+
+const TOURNAMENT_OPEN_STATUS = "open"
+const MATCH_PENDING_STATUS = "pending"
+
+// checkInterval как часто проверяются раунды, время которых вышло
+const checkInterval = 30 * time.Second
+
+// tournamentHints количество подсказок на участника в играх турнира
+const tournamentHints = 3
+
+// boardPresets настройки поля игр турнира по названию пресета
+var boardPresets = map[string]gamedto.CreateGameRequest{
+	"classic":  {Topology: "square"},
+	"no_guess": {Topology: "square", NoGuess: true, Fair: true},
+	"torus":    {Topology: "torus"},
+	"hex":      {Topology: "hex"},
+}
+
+type TournamentStorage interface {
+	CreateTournament(ctx context.Context, tournament *models.Tournament) error
+	GetTournaments(ctx context.Context) ([]*models.Tournament, error)
+	GetTournament(ctx context.Context, id string) (*models.TournamentDetails, error)
+	RegisterTournamentPlayer(ctx context.Context, id string, userID int64) error
+	StartTournament(ctx context.Context, id string, rounds int) error
+	AdvanceTournamentRound(ctx context.Context, id string, from, to int, deadline time.Time) (bool, error)
+	CloseTournament(ctx context.Context, id string, winnerID int64) (bool, error)
+	CreateTournamentMatch(ctx context.Context, tournamentID string, match *models.TournamentMatch) (int64, error)
+	GetTournamentMatchByGame(ctx context.Context, gameID string) (string, *models.TournamentMatch, error)
+	FinishTournamentMatch(ctx context.Context, tournamentID string, match *models.TournamentMatch, winnerID *int64, eliminate bool) error
+	GetExpiredTournaments(ctx context.Context, now time.Time) ([]string, error)
+}
+
+// GameService создаёт и закрывает игры матчей турнира
+type GameService interface {
+	CreateGame(ctx context.Context, userID int64, game *gamedto.CreateGameRequest) (string, error)
+	EnterGame(ctx context.Context, id string, userID int64, username string) error
+	StartMatchGame(ctx context.Context, id string, userID int64) error
+	DeleteGame(ctx context.Context, id string, userID int64) error
+	CloseGame(ctx context.Context, gameID string, winnerID int64, reveals map[string]*models.FieldReveal, hints map[string]int, stats map[string]*models.PlayerStats) error
+}
+
+type Tournament struct {
+	log     *slog.Logger
+	DB      TournamentStorage
+	gameSrv GameService
+	rdb     *redis.Redis
+}
+
+func New(log *slog.Logger, db TournamentStorage, gameSrv GameService, rdb *redis.Redis) *Tournament {
+	return &Tournament{log: log, DB: db, gameSrv: gameSrv, rdb: rdb}
+}
+
+func (t *Tournament) CreateTournament(ctx context.Context, userID int64, req *tournamentdto.CreateTournamentRequest) (string, error) {
+	const op = "tournament.CreateTournament"
+	log := t.log.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	id := uuid.New().String()
+	err := t.DB.CreateTournament(ctx, &models.Tournament{
+		ID:           id,
+		Title:        req.Title,
+		OrganizerID:  userID,
+		Format:       req.Format,
+		Preset:       req.Preset,
+		RoundMinutes: req.RoundMinutes,
+	})
+	if err != nil {
+		log.Error("error creating tournament", prettylogger.Err(err))
+		return "", err
+	}
+
+	log.Info("tournament created successfully", slog.String("tournament_id", id))
+	return id, nil
+}
+
+func (t *Tournament) GetTournaments(ctx context.Context) ([]*models.Tournament, error) {
+	const op = "tournament.GetTournaments"
+	log := t.log.With(slog.String("op", op))
+
+	tournaments, err := t.DB.GetTournaments(ctx)
+	if err != nil {
+		log.Error("error getting tournaments", prettylogger.Err(err))
+		return nil, err
+	}
+
+	log.Info("tournaments got successfully")
+	return tournaments, nil
+}
+
+// GetTournament возвращает турнир вместе с таблицей участников и сеткой матчей
+func (t *Tournament) GetTournament(ctx context.Context, id string) (*models.TournamentDetails, error) {
+	const op = "tournament.GetTournament"
+	log := t.log.With(slog.String("op", op), slog.String("tournament_id", id))
+
+	tournament, err := t.DB.GetTournament(ctx, id)
+	if err != nil {
+		log.Error("error getting tournament", prettylogger.Err(err))
+		return nil, err
+	}
+
+	log.Info("tournament got successfully")
+	return tournament, nil
+}
+
+func (t *Tournament) Register(ctx context.Context, id string, userID int64) error {
+	const op = "tournament.Register"
+	log := t.log.With(slog.String("op", op), slog.String("tournament_id", id), slog.Int64("user_id", userID))
+
+	err := t.DB.RegisterTournamentPlayer(ctx, id, userID)
+	if err != nil {
+		log.Error("error registering player", prettylogger.Err(err))
+		return err
+	}
+
+	log.Info("player registered successfully")
+	return nil
+}
+
+// Start закрывает регистрацию и запускает первый раунд. Начать турнир может только организатор
+func (t *Tournament) Start(ctx context.Context, id string, userID int64) error {
+	const op = "tournament.Start"
+	log := t.log.With(slog.String("op", op), slog.String("tournament_id", id), slog.Int64("user_id", userID))
+
+	tournament, err := t.DB.GetTournament(ctx, id)
+	if err != nil {
+		log.Error("error getting tournament", prettylogger.Err(err))
+		return err
+	}
+	if tournament.OrganizerID != userID {
+		log.Info("only organizer can start the tournament")
+		return fmt.Errorf("%s: %w", op, ErrOnlyOrganizerCanStart)
+	}
+	if tournament.Status != TOURNAMENT_OPEN_STATUS {
+		log.Info("tournament is not open")
+		return fmt.Errorf("%s: %w", op, storage.ErrTournamentNotOpen)
+	}
+	if len(tournament.Players) < 2 {
+		log.Info("not enough players to start the tournament")
+		return fmt.Errorf("%s: %w", op, ErrNotEnoughPlayers)
+	}
+
+	err = t.DB.StartTournament(ctx, id, roundsCount(len(tournament.Players)))
+	if err != nil {
+		log.Error("error starting tournament", prettylogger.Err(err))
+		return err
+	}
+	tournament.Rounds = roundsCount(len(tournament.Players))
+
+	err = t.startRound(ctx, log, tournament, 1)
+	if err != nil {
+		log.Error("error starting first round", prettylogger.Err(err))
+		return err
+	}
+
+	log.Info("tournament started successfully")
+	return nil
+}
+
+// GameClosed засчитывает результат игры матчу турнира. Игры вне турниров пропускаются
+func (t *Tournament) GameClosed(ctx context.Context, gameID string, winnerID int64) error {
+	const op = "tournament.GameClosed"
+	log := t.log.With(slog.String("op", op), slog.String("game_id", gameID), slog.Int64("winner_id", winnerID))
+
+	tournamentID, match, err := t.DB.GetTournamentMatchByGame(ctx, gameID)
+	if errors.Is(err, storage.ErrMatchNotFound) {
+		return nil
+	}
+	if err != nil {
+		log.Error("error getting tournament match", prettylogger.Err(err))
+		return err
+	}
+	log = log.With(slog.String("tournament_id", tournamentID))
+
+	tournament, err := t.DB.GetTournament(ctx, tournamentID)
+	if err != nil {
+		log.Error("error getting tournament", prettylogger.Err(err))
+		return err
+	}
+
+	var winner *int64
+	if winnerID == match.Player1ID || (match.Player2ID != nil && winnerID == *match.Player2ID) {
+		winner = &winnerID
+	}
+	err = t.finishMatch(ctx, tournament, match, winner)
+	if errors.Is(err, storage.ErrMatchFinished) {
+		return nil // матч уже засчитан по таймауту
+	}
+	if err != nil {
+		log.Error("error finishing tournament match", prettylogger.Err(err))
+		return err
+	}
+
+	log.Info("tournament match finished successfully")
+	return t.completeRound(ctx, log, tournamentID, match.Round)
+}
+
+// Run раз в checkInterval засчитывает по таймауту матчи раундов, время которых вышло
+func (t *Tournament) Run(ctx context.Context) {
+	const op = "tournament.Run"
+	log := t.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ids, err := t.DB.GetExpiredTournaments(ctx, time.Now().UTC())
+			if err != nil {
+				log.Error("error getting expired tournaments", prettylogger.Err(err))
+				continue
+			}
+			for _, id := range ids {
+				if err := t.expireRound(ctx, id); err != nil {
+					log.Error("error expiring tournament round", slog.String("tournament_id", id), prettylogger.Err(err))
+				}
+			}
+		}
+	}
+}
+
+// expireRound завершает незаконченные матчи текущего раунда: в олимпийской системе проходит
+// участник с лучшим посевом, в швейцарской засчитывается ничья. Игры матчей закрываются
+func (t *Tournament) expireRound(ctx context.Context, id string) error {
+	const op = "tournament.expireRound"
+	log := t.log.With(slog.String("op", op), slog.String("tournament_id", id))
+
+	tournament, err := t.DB.GetTournament(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, match := range tournament.Matches {
+		if match.Round != tournament.CurrentRound || match.Status != MATCH_PENDING_STATUS {
+			continue
+		}
+		var winner *int64
+		if tournament.Format == FORMAT_SINGLE_ELIMINATION {
+			winner = &match.Player1ID
+		}
+		err = t.finishMatch(ctx, tournament, match, winner)
+		if errors.Is(err, storage.ErrMatchFinished) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if match.GameID != nil {
+			t.closeExpiredGame(ctx, log, *match.GameID, winner)
+		}
+	}
+
+	log.Info("tournament round expired", slog.Int("round", tournament.CurrentRound))
+	return t.completeRound(ctx, log, id, tournament.CurrentRound)
+}
+
+// closeExpiredGame закрывает игру матча, время которого вышло, и распускает её комнату,
+// чтобы участники могли играть в следующем раунде
+func (t *Tournament) closeExpiredGame(ctx context.Context, log *slog.Logger, gameID string, winner *int64) {
+	var winnerID int64
+	if winner != nil {
+		winnerID = *winner
+	}
+	err := t.gameSrv.CloseGame(ctx, gameID, winnerID, nil, nil, nil)
+	if err != nil {
+		log.Error("error closing expired game", slog.String("game_id", gameID), prettylogger.Err(err))
+		return
+	}
+	err = t.rdb.PublishEvent(ctx, models.Event{
+		Type:   models.TypeDeleteGame,
+		GameID: gameID,
+	})
+	if err != nil {
+		log.Error("error pushing event", slog.String("event_type", "delete_game"), prettylogger.Err(err))
+	}
+}
+
+func (t *Tournament) finishMatch(ctx context.Context, tournament *models.TournamentDetails, match *models.TournamentMatch, winner *int64) error {
+	if winner == nil && tournament.Format == FORMAT_SINGLE_ELIMINATION {
+		winner = &match.Player1ID // в олимпийской системе ничьих нет, проходит лучший посев
+	}
+	return t.DB.FinishTournamentMatch(ctx, tournament.ID, match, winner, tournament.Format == FORMAT_SINGLE_ELIMINATION)
+}
+
+// completeRound запускает следующий раунд или завершает турнир, если все матчи раунда сыграны
+func (t *Tournament) completeRound(ctx context.Context, log *slog.Logger, id string, round int) error {
+	tournament, err := t.DB.GetTournament(ctx, id)
+	if err != nil {
+		log.Error("error getting tournament", prettylogger.Err(err))
+		return err
+	}
+	if tournament.CurrentRound != round {
+		return nil
+	}
+	for _, match := range tournament.Matches {
+		if match.Round == round && match.Status == MATCH_PENDING_STATUS {
+			return nil
+		}
+	}
+
+	if winnerID := tournamentWinner(tournament.Format, tournament.Players, round, tournament.Rounds); winnerID != 0 {
+		closed, err := t.DB.CloseTournament(ctx, id, winnerID)
+		if err != nil {
+			log.Error("error closing tournament", prettylogger.Err(err))
+			return err
+		}
+		if closed {
+			log.Info("tournament finished", slog.Int64("winner_id", winnerID))
+		}
+		return nil
+	}
+
+	return t.startRound(ctx, log, tournament, round+1)
+}
+
+// startRound создаёт игры матчей раунда и уведомляет участников о его начале.
+// Если игру матча создать не удалось, соперник проходит дальше без игры
+func (t *Tournament) startRound(ctx context.Context, log *slog.Logger, tournament *models.TournamentDetails, round int) error {
+	log = log.With(slog.Int("round", round))
+
+	pairs, bye := pairRound(tournament.Format, tournament.Players, tournament.Matches, round)
+	deadline := time.Now().UTC().Add(time.Duration(tournament.RoundMinutes) * time.Minute)
+	advanced, err := t.DB.AdvanceTournamentRound(ctx, tournament.ID, round-1, round, deadline)
+	if err != nil {
+		log.Error("error advancing tournament round", prettylogger.Err(err))
+		return err
+	}
+	if !advanced {
+		return nil
+	}
+
+	usernames := make(map[int64]string, len(tournament.Players))
+	for _, player := range tournament.Players {
+		usernames[player.ID] = player.Username
+	}
+
+	pairings := make([]*models.TournamentPairing, 0, len(pairs))
+	for _, pair := range pairs {
+		match := &models.TournamentMatch{Round: round, Player1ID: pair.player1, Player2ID: &pair.player2}
+		gameID, forfeitWinner := t.createMatchGame(ctx, log, tournament, round, pair, usernames)
+		if gameID != "" {
+			match.GameID = &gameID
+		}
+		match.ID, err = t.DB.CreateTournamentMatch(ctx, tournament.ID, match)
+		if err != nil {
+			log.Error("error creating tournament match", prettylogger.Err(err))
+			return err
+		}
+		if gameID == "" {
+			if err := t.finishMatch(ctx, tournament, match, &forfeitWinner); err != nil {
+				log.Error("error finishing forfeited match", prettylogger.Err(err))
+				return err
+			}
+			continue
+		}
+		pairings = append(pairings, &models.TournamentPairing{GameID: gameID, Players: []int64{pair.player1, pair.player2}})
+	}
+	if bye != 0 {
+		match := &models.TournamentMatch{Round: round, Player1ID: bye}
+		match.ID, err = t.DB.CreateTournamentMatch(ctx, tournament.ID, match)
+		if err != nil {
+			log.Error("error creating tournament match", prettylogger.Err(err))
+			return err
+		}
+		if err := t.finishMatch(ctx, tournament, match, &bye); err != nil {
+			log.Error("error finishing bye match", prettylogger.Err(err))
+			return err
+		}
+	}
+
+	if err := t.publishRound(ctx, tournament, round, deadline, pairings); err != nil {
+		log.Error("error pushing event", slog.String("event_type", "tournament_round"), prettylogger.Err(err))
+	}
+	log.Info("tournament round started")
+
+	if len(pairings) == 0 {
+		return t.completeRound(ctx, log, tournament.ID, round)
+	}
+	return nil
+}
+
+// createMatchGame создаёт закрытую игру матча от имени первого участника, добавляет в неё второго и начинает её.
+// При ошибке возвращает пустой id и участника, которому засчитывается победа
+func (t *Tournament) createMatchGame(ctx context.Context, log *slog.Logger, tournament *models.TournamentDetails, round int, pair pairing, usernames map[int64]string) (string, int64) {
+	req := boardPresets[tournament.Preset]
+	isPublic := false
+	hints := tournamentHints
+	req.Title = fmt.Sprintf("%s: раунд %d", tournament.Title, round)
+	req.IsPublic = &isPublic
+	req.Hints = &hints
+	req.Lives = 1
+
+	gameID, err := t.gameSrv.CreateGame(ctx, pair.player1, &req)
+	if err != nil {
+		log.Warn("error creating match game", slog.Int64("user_id", pair.player1), prettylogger.Err(err))
+		return "", pair.player2
+	}
+	err = t.gameSrv.EnterGame(ctx, gameID, pair.player2, usernames[pair.player2])
+	if err != nil {
+		log.Warn("error entering match game", slog.Int64("user_id", pair.player2), prettylogger.Err(err))
+		if err := t.gameSrv.DeleteGame(ctx, gameID, pair.player1); err != nil {
+			log.Error("error deleting match game", prettylogger.Err(err))
+		}
+		return "", pair.player1
+	}
+	// Игра начинается сразу, иначе до нажатия старта её могла бы удалить периодическая очистка старых игр
+	err = t.gameSrv.StartMatchGame(ctx, gameID, pair.player1)
+	if err != nil {
+		log.Error("error starting match game", slog.String("game_id", gameID), prettylogger.Err(err))
+	}
+	return gameID, 0
+}
+
+func (t *Tournament) publishRound(ctx context.Context, tournament *models.TournamentDetails, round int, deadline time.Time, pairings []*models.TournamentPairing) error {
+	payload, err := json.Marshal(models.TournamentRoundEvent{
+		TournamentID: tournament.ID,
+		Title:        tournament.Title,
+		Round:        round,
+		Deadline:     deadline,
+		Pairings:     pairings,
+	})
+	if err != nil {
+		return err
+	}
+	return t.rdb.PublishEvent(ctx, models.Event{
+		Type:    models.TypeTournamentRound,
+		UserID:  tournament.OrganizerID,
+		Payload: payload,
+	})
+}
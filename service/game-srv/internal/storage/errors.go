@@ -16,4 +16,14 @@ var (
 	ErrIncorrectCountOfPlayers  = errors.New("Некорректное количество игроков, чтобы начать игру")
 	ErrUserExists               = errors.New("пользователь уже существует")
 	ErrUserNotFound             = errors.New("пользователь не найден")
+	ErrTournamentNotFound       = errors.New("Турнир не найден")
+	ErrTournamentNotOpen        = errors.New("Регистрация на турнир закрыта")
+	ErrAlreadyRegistered        = errors.New("Ты уже зарегистрирован на турнир")
+	ErrMatchNotFound            = errors.New("Матч турнира не найден")
+	ErrMatchFinished            = errors.New("Матч турнира уже завершён")
+	ErrDailyAlreadyPlayed       = errors.New("Сегодняшнее испытание уже сыграно")
+	ErrDailyAttemptNotFound     = errors.New("Попытка ежедневного испытания не найдена")
+	ErrFriendRequestExists      = errors.New("Заявка в друзья уже отправлена")
+	ErrAlreadyFriends           = errors.New("Вы уже друзья")
+	ErrFriendNotFound           = errors.New("Друг или заявка в друзья не найдены")
 )
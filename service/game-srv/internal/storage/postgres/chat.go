@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ms4me/game/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveChatMessages сохраняет сообщения чата игры. Уже сохранённые сообщения обновляются, а удалённые автором
+// убираются, поэтому чат можно архивировать повторно. Сообщения удалённой игры пропускаются
+func (s *Storage) SaveChatMessages(ctx context.Context, gameID string, messages []*models.ChatMessage) (int, error) {
+	const op = "storage.postgres.SaveChatMessages"
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = fmt.Errorf("rollback failed: %v, original error: %w", rollbackErr, err)
+			}
+		} else {
+			if cErr := tx.Commit(ctx); cErr != nil {
+				err = fmt.Errorf("commit failed: %v, original error: %w", cErr, err)
+			}
+		}
+	}()
+
+	var exists int
+	err = tx.QueryRow(ctx, "SELECT 1 FROM games WHERE id = $1 FOR SHARE", gameID).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = nil // игра уже удалена, сохранять нечего
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	saved := 0
+	for _, message := range messages {
+		if message.Deleted {
+			_, err = tx.Exec(ctx, "DELETE FROM chat_messages WHERE id = $1 AND game_id = $2", message.ID, gameID)
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", op, err)
+			}
+			continue
+		}
+		_, err = tx.Exec(ctx, `
+		INSERT INTO chat_messages (id, game_id, creator_id, creator_username, text, created_at, edited_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET text = EXCLUDED.text, edited_at = EXCLUDED.edited_at`,
+			message.ID, gameID, message.CreatorID, message.CreatorUsername, message.Text, message.CreatedAt, message.EditedAt)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		saved++
+	}
+
+	return saved, nil
+}
+
+func (s *Storage) GetChatMessages(ctx context.Context, gameID string) ([]*models.ChatMessage, error) {
+	const op = "storage.postgres.GetChatMessages"
+
+	rows, err := s.DB.Query(ctx, `
+	SELECT id, creator_id, creator_username, text, created_at, edited_at
+	FROM chat_messages
+	WHERE game_id = $1
+	ORDER BY created_at, id`, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	messages := make([]*models.ChatMessage, 0)
+	for rows.Next() {
+		var message models.ChatMessage
+		if err := rows.Scan(&message.ID, &message.CreatorID, &message.CreatorUsername, &message.Text, &message.CreatedAt, &message.EditedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		messages = append(messages, &message)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"ms4me/game/internal/models"
+)
+
+func (s *Storage) SaveCommitment(ctx context.Context, gameID string, userID int64, commitment string) error {
+	const op = "storage.postgres.SaveCommitment"
+
+	_, err := s.DB.Exec(ctx, `
+	INSERT INTO field_commitments (game_id, user_id, commitment)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (game_id, user_id) DO NOTHING`, gameID, userID, commitment)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevealCommitment сохраняет раскрытие поля. Раскрытие, не совпадающее с ранее сохранённой commitment, игнорируется
+func (s *Storage) RevealCommitment(ctx context.Context, gameID string, userID int64, reveal *models.FieldReveal) error {
+	const op = "storage.postgres.RevealCommitment"
+
+	_, err := s.DB.Exec(ctx, `
+	UPDATE field_commitments
+	SET seed = $1, origin_row = $2, origin_col = $3, layout = $4
+	WHERE game_id = $5 AND user_id = $6 AND commitment = $7`,
+		reveal.Seed, reveal.Origin.Row, reveal.Origin.Col, reveal.Layout, gameID, userID, reveal.Commitment)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetCommitments(ctx context.Context, gameID string) ([]*models.FieldCommitment, error) {
+	const op = "storage.postgres.GetCommitments"
+
+	rows, err := s.DB.Query(ctx, `
+	SELECT user_id, commitment, seed, origin_row, origin_col, layout
+	FROM field_commitments
+	WHERE game_id = $1`, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	commitments := make([]*models.FieldCommitment, 0)
+	for rows.Next() {
+		var commitment models.FieldCommitment
+		var originRow, originCol *int
+		if err := rows.Scan(
+			&commitment.UserID, &commitment.Commitment, &commitment.Seed,
+			&originRow, &originCol, &commitment.Layout,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if originRow != nil && originCol != nil {
+			commitment.Origin = &models.CellPoint{Row: *originRow, Col: *originCol}
+		}
+		commitments = append(commitments, &commitment)
+	}
+
+	return commitments, nil
+}
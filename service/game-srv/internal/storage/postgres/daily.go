@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ms4me/game/internal/models"
+	"ms4me/game/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const dailyAttemptColumns = `a.user_id, u.username, a.game_id, a.started_at, a.finished_at, a.won, a.moves,
+	(EXTRACT(EPOCH FROM a.finished_at - a.started_at) * 1000)::BIGINT AS duration_ms`
+
+func scanDailyAttempt(row pgx.Row, attempt *models.DailyAttempt) error {
+	return row.Scan(&attempt.UserID, &attempt.Username, &attempt.GameID, &attempt.StartedAt, &attempt.FinishedAt,
+		&attempt.Won, &attempt.Moves, &attempt.DurationMS)
+}
+
+// GetDailySeed возвращает сид поля испытания дня day. Если испытания ещё нет, оно создаётся с сидом seed
+func (s *Storage) GetDailySeed(ctx context.Context, day time.Time, seed int64) (int64, error) {
+	const op = "storage.postgres.GetDailySeed"
+
+	_, err := s.DB.Exec(ctx, "INSERT INTO daily_challenges (day, seed) VALUES ($1, $2) ON CONFLICT (day) DO NOTHING", day, seed)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	err = s.DB.QueryRow(ctx, "SELECT seed FROM daily_challenges WHERE day = $1", day).Scan(&seed)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return seed, nil
+}
+
+// CreateDailyGame создаёт одиночную игру попытки испытания дня day.
+// Каждый пользователь может начать испытание дня только один раз
+func (s *Storage) CreateDailyGame(ctx context.Context, game *models.Game, seed int64, day time.Time) (err error) {
+	const op = "storage.postgres.CreateDailyGame"
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = fmt.Errorf("rollback failed: %v, original error: %w", rollbackErr, err)
+			}
+		} else {
+			if cErr := tx.Commit(ctx); cErr != nil {
+				err = fmt.Errorf("commit failed: %v, original error: %w", cErr, err)
+			}
+		}
+	}()
+
+	err = insertSoloGame(ctx, tx, game, &seed, &day)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "INSERT INTO daily_attempts (day, user_id, game_id) VALUES ($1, $2, $3)", day, game.OwnerID, game.ID)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return storage.ErrDailyAlreadyPlayed
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetDailyStats возвращает, сколько игроков начали испытание дня day и сколько его прошли
+func (s *Storage) GetDailyStats(ctx context.Context, day time.Time) (int, int, error) {
+	const op = "storage.postgres.GetDailyStats"
+
+	var players, winners int
+	err := s.DB.QueryRow(ctx, "SELECT COUNT(*), COUNT(*) FILTER (WHERE won) FROM daily_attempts WHERE day = $1", day).
+		Scan(&players, &winners)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return players, winners, nil
+}
+
+func (s *Storage) GetDailyAttempt(ctx context.Context, day time.Time, userID int64) (*models.DailyAttempt, error) {
+	const op = "storage.postgres.GetDailyAttempt"
+
+	var attempt models.DailyAttempt
+	err := scanDailyAttempt(s.DB.QueryRow(ctx, `
+	SELECT `+dailyAttemptColumns+`
+	FROM daily_attempts a
+	JOIN users u ON u.id = a.user_id
+	WHERE a.day = $1 AND a.user_id = $2`, day, userID), &attempt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrDailyAttemptNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &attempt, nil
+}
+
+// GetDailyAttemptByGame ищет незавершённую попытку испытания, сыгранную в игре gameID
+func (s *Storage) GetDailyAttemptByGame(ctx context.Context, gameID string) (*models.DailyAttempt, error) {
+	const op = "storage.postgres.GetDailyAttemptByGame"
+
+	var attempt models.DailyAttempt
+	err := scanDailyAttempt(s.DB.QueryRow(ctx, `
+	SELECT `+dailyAttemptColumns+`
+	FROM daily_attempts a
+	JOIN users u ON u.id = a.user_id
+	WHERE a.game_id = $1 AND a.finished_at IS NULL`, gameID), &attempt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrDailyAttemptNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &attempt, nil
+}
+
+// FinishDailyAttempt сохраняет итог попытки испытания, время прохождения считается от создания игры
+func (s *Storage) FinishDailyAttempt(ctx context.Context, gameID string, won bool, moves int) error {
+	const op = "storage.postgres.FinishDailyAttempt"
+
+	_, err := s.DB.Exec(ctx, `
+	UPDATE daily_attempts SET finished_at = CURRENT_TIMESTAMP, won = $2, moves = $3
+	WHERE game_id = $1 AND finished_at IS NULL`, gameID, won, moves)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetDailyLeaderboard возвращает прошедших испытание дня day: сначала быстрейшие, при равенстве - сделавшие меньше ходов
+func (s *Storage) GetDailyLeaderboard(ctx context.Context, day time.Time, limit int) ([]*models.DailyAttempt, error) {
+	const op = "storage.postgres.GetDailyLeaderboard"
+
+	rows, err := s.DB.Query(ctx, `
+	SELECT `+dailyAttemptColumns+`
+	FROM daily_attempts a
+	JOIN users u ON u.id = a.user_id
+	WHERE a.day = $1 AND a.won
+	ORDER BY a.finished_at - a.started_at, a.moves, a.finished_at
+	LIMIT $2`, day, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	attempts := make([]*models.DailyAttempt, 0)
+	for rows.Next() {
+		var attempt models.DailyAttempt
+		if err := scanDailyAttempt(rows, &attempt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		attempts = append(attempts, &attempt)
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ms4me/game/internal/models"
+	"ms4me/game/internal/storage"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// RequestFriend отправляет заявку в друзья. Если встречная заявка уже есть, она принимается,
+// тогда первое значение true
+func (s *Storage) RequestFriend(ctx context.Context, userID, friendID int64) (accepted bool, err error) {
+	const op = "storage.postgres.RequestFriend"
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = fmt.Errorf("rollback failed: %v, original error: %w", rollbackErr, err)
+			}
+		} else {
+			if cErr := tx.Commit(ctx); cErr != nil {
+				err = fmt.Errorf("commit failed: %v, original error: %w", cErr, err)
+			}
+		}
+	}()
+
+	var requesterID int64
+	var status string
+	err = tx.QueryRow(ctx, `
+	SELECT user_id, status FROM friends
+	WHERE (user_id = $1 AND friend_id = $2) OR (user_id = $2 AND friend_id = $1)
+	FOR UPDATE`, userID, friendID).Scan(&requesterID, &status)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		err = nil
+	case err != nil:
+		return false, fmt.Errorf("%s: %w", op, err)
+	case status == "accepted":
+		return false, storage.ErrAlreadyFriends
+	case requesterID == userID:
+		return false, storage.ErrFriendRequestExists
+	default:
+		_, err = tx.Exec(ctx, "UPDATE friends SET status = 'accepted' WHERE user_id = $1 AND friend_id = $2", friendID, userID)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+		return true, nil
+	}
+
+	// ботов в друзья не добавить
+	cmd, err := tx.Exec(ctx, `
+	INSERT INTO friends (user_id, friend_id)
+	SELECT $1, id FROM users WHERE id = $2 AND is_bot = false`, userID, friendID)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return false, storage.ErrFriendRequestExists
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return false, storage.ErrUserNotFound
+	}
+
+	return false, nil
+}
+
+// AcceptFriend принимает заявку в друзья, которую fromID отправил userID
+func (s *Storage) AcceptFriend(ctx context.Context, userID, fromID int64) error {
+	const op = "storage.postgres.AcceptFriend"
+
+	cmd, err := s.DB.Exec(ctx, `
+	UPDATE friends SET status = 'accepted'
+	WHERE user_id = $2 AND friend_id = $1 AND status = 'pending'`, userID, fromID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return storage.ErrFriendNotFound
+	}
+
+	return nil
+}
+
+// RemoveFriend удаляет друга, а также отклоняет или отзывает заявку в друзья
+func (s *Storage) RemoveFriend(ctx context.Context, userID, friendID int64) error {
+	const op = "storage.postgres.RemoveFriend"
+
+	cmd, err := s.DB.Exec(ctx, `
+	DELETE FROM friends
+	WHERE (user_id = $1 AND friend_id = $2) OR (user_id = $2 AND friend_id = $1)`, userID, friendID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return storage.ErrFriendNotFound
+	}
+
+	return nil
+}
+
+// GetFriends возвращает друзей пользователя и заявки в друзья в обе стороны
+func (s *Storage) GetFriends(ctx context.Context, userID int64) ([]*models.Friend, error) {
+	const op = "storage.postgres.GetFriends"
+
+	rows, err := s.DB.Query(ctx, `
+	SELECT u.id, u.username, f.status, f.user_id <> $1 AS incoming
+	FROM friends f
+	JOIN users u ON u.id = CASE WHEN f.user_id = $1 THEN f.friend_id ELSE f.user_id END
+	WHERE f.user_id = $1 OR f.friend_id = $1
+	ORDER BY f.status, u.username`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	friends := make([]*models.Friend, 0)
+	for rows.Next() {
+		var friend models.Friend
+		if err := rows.Scan(&friend.ID, &friend.Username, &friend.Status, &friend.Incoming); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		friends = append(friends, &friend)
+	}
+
+	return friends, nil
+}
+
+func (s *Storage) AreFriends(ctx context.Context, userID, friendID int64) (bool, error) {
+	const op = "storage.postgres.AreFriends"
+
+	var exists bool
+	err := s.DB.QueryRow(ctx, `
+	SELECT EXISTS (
+		SELECT 1 FROM friends
+		WHERE ((user_id = $1 AND friend_id = $2) OR (user_id = $2 AND friend_id = $1)) AND status = 'accepted'
+	)`, userID, friendID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+// CreateDirectMessage сохраняет личное сообщение и заполняет его id и время создания
+func (s *Storage) CreateDirectMessage(ctx context.Context, message *models.DirectMessage) error {
+	const op = "storage.postgres.CreateDirectMessage"
+
+	err := s.DB.QueryRow(ctx, `
+	INSERT INTO direct_messages (sender_id, recipient_id, text)
+	VALUES ($1, $2, $3)
+	RETURNING id, created_at`, message.SenderID, message.RecipientID, message.Text).Scan(&message.ID, &message.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetDirectMessages возвращает не больше limit последних сообщений переписки двух пользователей
+// с id меньше before (0 - без ограничения) в порядке отправки
+func (s *Storage) GetDirectMessages(ctx context.Context, userID, friendID int64, before int64, limit int) ([]*models.DirectMessage, error) {
+	const op = "storage.postgres.GetDirectMessages"
+
+	rows, err := s.DB.Query(ctx, `
+	SELECT m.id, m.sender_id, u.username, m.recipient_id, m.text, m.created_at
+	FROM direct_messages m
+	JOIN users u ON u.id = m.sender_id
+	WHERE LEAST(m.sender_id, m.recipient_id) = LEAST($1::INT, $2::INT)
+	AND GREATEST(m.sender_id, m.recipient_id) = GREATEST($1::INT, $2::INT)
+	AND ($3 = 0 OR m.id < $3)
+	ORDER BY m.id DESC
+	LIMIT $4`, userID, friendID, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	messages := make([]*models.DirectMessage, 0)
+	for rows.Next() {
+		var message models.DirectMessage
+		if err := rows.Scan(&message.ID, &message.SenderID, &message.SenderUsername, &message.RecipientID, &message.Text, &message.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		messages = append(messages, &message)
+	}
+	slices.Reverse(messages)
+
+	return messages, nil
+}
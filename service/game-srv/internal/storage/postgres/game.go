@@ -8,8 +8,10 @@ import (
 	gamedto "ms4me/game/internal/http/dto/game"
 	"ms4me/game/internal/models"
 	"ms4me/game/internal/storage"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
@@ -51,11 +53,11 @@ func (s *Storage) CreateGame(ctx context.Context, game *models.Game, userID int6
 	var gameID string
 	err = tx.QueryRow(ctx, `
 	INSERT INTO games
-	(id, title, mines, rows, cols, owner_id, is_public)
-	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	(id, title, mines, rows, cols, owner_id, is_public, no_guess, fair, hints, topology, questions, scoring, lives, coop)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	RETURNING id`,
 		game.ID, game.Title, game.Mines, game.Rows, game.Cols,
-		game.OwnerID, game.IsPublic).Scan(&gameID)
+		game.OwnerID, game.IsPublic, game.NoGuess, game.Fair, game.Hints, game.Topology, game.Questions, game.Scoring, game.Lives, game.Coop).Scan(&gameID)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -75,11 +77,72 @@ func (s *Storage) CreateGame(ctx context.Context, game *models.Game, userID int6
 	return gameID, nil
 }
 
+// CreateSoloGame создаёт одиночную игру сразу начатой: ждать соперника и проверять готовность не нужно
+func (s *Storage) CreateSoloGame(ctx context.Context, game *models.Game, seed *int64) (err error) {
+	const op = "storage.postgres.CreateSoloGame"
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = fmt.Errorf("rollback failed: %v, original error: %w", rollbackErr, err)
+			}
+		} else {
+			if cErr := tx.Commit(ctx); cErr != nil {
+				err = fmt.Errorf("commit failed: %v, original error: %w", cErr, err)
+			}
+		}
+	}()
+
+	return insertSoloGame(ctx, tx, game, seed, nil)
+}
+
+// insertSoloGame добавляет начатую игру с одним участником - её создателем. seed и day заданы только у испытания дня
+func insertSoloGame(ctx context.Context, tx pgx.Tx, game *models.Game, seed *int64, day *time.Time) error {
+	const op = "storage.postgres.insertSoloGame"
+
+	var countGames int // Кол-во игр, в которых сейчас находится пользователь
+	err := tx.QueryRow(ctx, `
+	SELECT COUNT(*) FROM players p
+	LEFT JOIN games g
+	ON p.game_id = g.id
+	WHERE g.status != 'closed' AND p.user_id = $1`, game.OwnerID,
+	).Scan(&countGames)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if countGames != 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAlreadyPlaying)
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO games
+	(id, title, mines, rows, cols, owner_id, is_public, no_guess, fair, hints, topology, questions, scoring, lives, coop,
+	solo, status, max_players, seed, daily)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, true, 'started', 1, $16, $17)`,
+		game.ID, game.Title, game.Mines, game.Rows, game.Cols,
+		game.OwnerID, game.IsPublic, game.NoGuess, game.Fair, game.Hints, game.Topology, game.Questions, game.Scoring, game.Lives, game.Coop,
+		seed, day)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, "INSERT INTO players (user_id, game_id) VALUES ($1, $2)", game.OwnerID, game.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 func (s *Storage) GetGames(ctx context.Context, filter *gamedto.GetGamesRequest) ([]*models.Game, error) {
 	const op = "storage.postgres.GetGames"
 
 	builder := sq.Select("g.id", "title", "mines", "rows", "cols", "owner_id", "created_at", "status", "is_public", "max_players",
-		"(SELECT COUNT(*) FROM players WHERE game_id = g.id) AS players_now", "u.username", "g.winner_id").
+		"(SELECT COUNT(*) FROM players WHERE game_id = g.id) AS players_now", "u.username", "g.winner_id", "g.no_guess", "g.fair", "g.hints", "g.topology", "g.questions", "g.scoring", "g.lives", "g.coop", "g.solo").
 		From("games g").
 		Join("users u ON u.id = g.owner_id").
 		Where("is_public = true").
@@ -116,7 +179,7 @@ func (s *Storage) GetGames(ctx context.Context, filter *gamedto.GetGamesRequest)
 			&game.ID, &game.Title, &game.Mines, &game.Rows,
 			&game.Cols, &game.OwnerID, &game.CreatedAt,
 			&game.Status, &game.IsPublic, &game.MaxPlayers,
-			&game.PlayersCount, &game.OwnerName, &game.WinnerID,
+			&game.PlayersCount, &game.OwnerName, &game.WinnerID, &game.NoGuess, &game.Fair, &game.Hints, &game.Topology, &game.Questions, &game.Scoring, &game.Lives, &game.Coop, &game.Solo,
 		); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -134,7 +197,7 @@ func (s *Storage) GetGameByIDUserID(ctx context.Context, id string, userID int64
     g.id, g.title, g.mines, g.rows, g.cols, 
     g.owner_id, g.status, g.created_at, g.is_public, g.max_players,
     COUNT(p.user_id) AS players_now,
-    u.username, g.winner_id
+    u.username, g.winner_id, g.no_guess, g.fair, g.hints, g.topology, g.questions, g.scoring, g.lives, g.coop, g.solo
 	FROM games g
 	JOIN users u ON u.id = g.owner_id
 	LEFT JOIN players p ON p.game_id = g.id
@@ -149,7 +212,7 @@ func (s *Storage) GetGameByIDUserID(ctx context.Context, id string, userID int64
 	if err := row.Scan(
 		&game.ID, &game.Title, &game.Mines, &game.Rows,
 		&game.Cols, &game.OwnerID, &game.Status, &game.CreatedAt,
-		&game.IsPublic, &game.MaxPlayers, &game.PlayersCount, &game.OwnerName, &game.WinnerID,
+		&game.IsPublic, &game.MaxPlayers, &game.PlayersCount, &game.OwnerName, &game.WinnerID, &game.NoGuess, &game.Fair, &game.Hints, &game.Topology, &game.Questions, &game.Scoring, &game.Lives, &game.Coop, &game.Solo,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrGameNotFoundOrNotYourOwn
@@ -159,7 +222,7 @@ func (s *Storage) GetGameByIDUserID(ctx context.Context, id string, userID int64
 
 	players := make([]*models.User, 0)
 	rows, err := s.DB.Query(ctx, `
-	SELECT u.id, u.username
+	SELECT u.id, u.username, p.hints_used, p.moves, p.cells_opened, u.is_bot
 	FROM users u
 	JOIN players p ON p.user_id = u.id
 	WHERE p.game_id = $1`, id)
@@ -169,7 +232,7 @@ func (s *Storage) GetGameByIDUserID(ctx context.Context, id string, userID int64
 
 	for rows.Next() {
 		var player models.User
-		err := rows.Scan(&player.ID, &player.Username)
+		err := rows.Scan(&player.ID, &player.Username, &player.HintsUsed, &player.Moves, &player.CellsOpened, &player.IsBot)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -185,7 +248,7 @@ func (s *Storage) GetGameByID(ctx context.Context, id string) (*models.GameDetai
 
 	row := s.DB.QueryRow(ctx, `
 	SELECT g.id, title, mines, rows, cols, owner_id, status, created_at, is_public, max_players,
-	(SELECT COUNT(*) FROM players WHERE game_id = g.id) AS players_now, u.username, g.winner_id
+	(SELECT COUNT(*) FROM players WHERE game_id = g.id) AS players_now, u.username, g.winner_id, g.no_guess, g.fair, g.hints, g.topology, g.questions, g.scoring, g.lives, g.coop, g.solo, g.seed, g.daily
 	FROM games g
 	JOIN users u ON u.id = g.owner_id
 	WHERE g.id = $1`, id)
@@ -194,7 +257,7 @@ func (s *Storage) GetGameByID(ctx context.Context, id string) (*models.GameDetai
 	if err := row.Scan(
 		&game.ID, &game.Title, &game.Mines, &game.Rows,
 		&game.Cols, &game.OwnerID, &game.Status, &game.CreatedAt,
-		&game.IsPublic, &game.MaxPlayers, &game.PlayersCount, &game.OwnerName, &game.WinnerID,
+		&game.IsPublic, &game.MaxPlayers, &game.PlayersCount, &game.OwnerName, &game.WinnerID, &game.NoGuess, &game.Fair, &game.Hints, &game.Topology, &game.Questions, &game.Scoring, &game.Lives, &game.Coop, &game.Solo, &game.Seed, &game.Daily,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrGameNotFound
@@ -204,7 +267,7 @@ func (s *Storage) GetGameByID(ctx context.Context, id string) (*models.GameDetai
 
 	players := make([]*models.User, 0)
 	rows, err := s.DB.Query(ctx, `
-	SELECT u.id, u.username
+	SELECT u.id, u.username, p.hints_used, p.moves, p.cells_opened, u.is_bot
 	FROM users u
 	JOIN players p ON p.user_id = u.id
 	WHERE p.game_id = $1`, id)
@@ -214,7 +277,7 @@ func (s *Storage) GetGameByID(ctx context.Context, id string) (*models.GameDetai
 
 	for rows.Next() {
 		var player models.User
-		err := rows.Scan(&player.ID, &player.Username)
+		err := rows.Scan(&player.ID, &player.Username, &player.HintsUsed, &player.Moves, &player.CellsOpened, &player.IsBot)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -228,7 +291,8 @@ func (s *Storage) GetGameByID(ctx context.Context, id string) (*models.GameDetai
 func (s *Storage) UpdateGame(ctx context.Context, id string, userID int64, game *models.Game) error {
 	const op = "storage.postgres.UpdateGame"
 
-	queryBuilder := sq.Update("games").Where(sq.Eq{"id": id, "owner_id": userID}).PlaceholderFormat(sq.Dollar)
+	// менять можно только ещё не начатую игру
+	queryBuilder := sq.Update("games").Where(sq.Eq{"id": id, "owner_id": userID, "status": "open"}).PlaceholderFormat(sq.Dollar)
 	if game.Title != "" {
 		queryBuilder = queryBuilder.Set("title", game.Title)
 	}
@@ -309,7 +373,7 @@ func (s *Storage) DeleteGame(ctx context.Context, id string, userID int64) error
 	return nil
 }
 
-func (s *Storage) StartGame(ctx context.Context, id string, userID int64) error {
+func (s *Storage) StartGame(ctx context.Context, id string, userID int64, seed *int64) error {
 	const op = "storage.postgres.StartGame"
 
 	tx, err := s.DB.Begin(ctx)
@@ -338,7 +402,7 @@ func (s *Storage) StartGame(ctx context.Context, id string, userID int64) error
 		return fmt.Errorf("%s: %w", op, storage.ErrIncorrectCountOfPlayers)
 	}
 
-	result, err := tx.Exec(ctx, "UPDATE games SET status = 'started' WHERE id = $1", id)
+	result, err := tx.Exec(ctx, "UPDATE games SET status = 'started', seed = $2 WHERE id = $1", id, seed)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -407,11 +471,53 @@ func (s *Storage) EnterGame(ctx context.Context, id string, userID int64) error
 	return nil
 }
 
+// AddBotToGame добавляет бота в игру. В отличие от EnterGame не проверяет участие в других играх,
+// один и тот же бот может играть в нескольких играх одновременно
+func (s *Storage) AddBotToGame(ctx context.Context, id string, botID int64) error {
+	const op = "storage.postgres.AddBotToGame"
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = fmt.Errorf("rollback failed: %v, original error: %w", rollbackErr, err)
+			}
+		} else {
+			if cErr := tx.Commit(ctx); cErr != nil {
+				err = fmt.Errorf("commit failed: %v, original error: %w", cErr, err)
+			}
+		}
+	}()
+
+	var countPlayers int
+	err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM players WHERE game_id = $1", id).Scan(&countPlayers)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if countPlayers >= models.MaxPlayers {
+		return fmt.Errorf("%s: %w", op, storage.ErrMaxPlayers)
+	}
+
+	_, err = tx.Exec(ctx, "INSERT INTO players (game_id, user_id) VALUES ($1, $2)", id, botID)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return fmt.Errorf("%s: %w", op, storage.ErrPlayerAlreadyExists)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 func (s *Storage) GetUserGames(ctx context.Context, userID int64) ([]*models.Game, error) {
 	const op = "storage.postgres.GetUserGames"
 
 	builder := sq.Select("g.id", "title", "mines", "rows", "cols", "owner_id", "created_at", "status", "is_public", "max_players",
-		"(SELECT COUNT(*) FROM players WHERE game_id = g.id) AS players_now", "u.username", "g.winner_id").
+		"(SELECT COUNT(*) FROM players WHERE game_id = g.id) AS players_now", "u.username", "g.winner_id", "g.no_guess", "g.fair", "g.hints", "g.topology", "g.questions", "g.scoring", "g.lives", "g.coop", "g.solo").
 		From("games g").
 		Join("players p ON p.game_id = g.id").
 		Join("users u ON u.id = g.owner_id").
@@ -434,7 +540,7 @@ func (s *Storage) GetUserGames(ctx context.Context, userID int64) ([]*models.Gam
 			&game.ID, &game.Title, &game.Mines, &game.Rows,
 			&game.Cols, &game.OwnerID, &game.CreatedAt,
 			&game.Status, &game.IsPublic, &game.MaxPlayers,
-			&game.PlayersCount, &game.OwnerName, &game.WinnerID,
+			&game.PlayersCount, &game.OwnerName, &game.WinnerID, &game.NoGuess, &game.Fair, &game.Hints, &game.Topology, &game.Questions, &game.Scoring, &game.Lives, &game.Coop, &game.Solo,
 		); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -444,6 +550,29 @@ func (s *Storage) GetUserGames(ctx context.Context, userID int64) ([]*models.Gam
 	return games, nil
 }
 
+// GetUserStats считает статистику пользователя по законченным играм, в которых он участвовал
+func (s *Storage) GetUserStats(ctx context.Context, userID int64) (*models.UserStats, error) {
+	const op = "storage.postgres.GetUserStats"
+
+	var stats models.UserStats
+	err := s.DB.QueryRow(ctx, `
+	SELECT COUNT(*),
+	COUNT(*) FILTER (WHERE g.winner_id = $1),
+	COUNT(*) FILTER (WHERE g.solo),
+	COUNT(*) FILTER (WHERE g.solo AND g.winner_id = $1),
+	COALESCE(SUM(p.moves), 0),
+	COALESCE(SUM(p.cells_opened), 0)
+	FROM players p
+	JOIN games g ON g.id = p.game_id
+	WHERE p.user_id = $1 AND g.status = 'closed'`, userID).
+		Scan(&stats.Played, &stats.Won, &stats.SoloPlayed, &stats.SoloWon, &stats.Moves, &stats.CellsOpened)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &stats, nil
+}
+
 func (s *Storage) ExitGame(ctx context.Context, id string, userID int64) error {
 	const op = "storage.postgres.ExitGame"
 
@@ -485,6 +614,30 @@ func (s *Storage) UpdateGameStatus(ctx context.Context, id string, status string
 	return nil
 }
 
+func (s *Storage) UpdateHintsUsed(ctx context.Context, id string, userID int64, hintsUsed int) error {
+	const op = "storage.postgres.UpdateHintsUsed"
+
+	_, err := s.DB.Exec(ctx, "UPDATE players SET hints_used = $1 WHERE game_id = $2 AND user_id = $3", hintsUsed, id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdatePlayerStats сохраняет итоги участника за игру
+func (s *Storage) UpdatePlayerStats(ctx context.Context, id string, userID int64, stats *models.PlayerStats) error {
+	const op = "storage.postgres.UpdatePlayerStats"
+
+	_, err := s.DB.Exec(ctx, "UPDATE players SET moves = $1, cells_opened = $2 WHERE game_id = $3 AND user_id = $4",
+		stats.Moves, stats.CellsOpened, id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 func (s *Storage) UpdateWinner(ctx context.Context, id string, winnerID int64) error {
 	const op = "storage.postgres.UpdateGameStatus"
 
@@ -0,0 +1,336 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ms4me/game/internal/models"
+	"ms4me/game/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const tournamentColumns = `t.id, t.title, t.organizer_id, u.username, t.format, t.preset, t.round_minutes, t.rounds, t.current_round,
+	t.round_deadline, t.status, t.winner_id, t.created_at,
+	(SELECT COUNT(*) FROM tournament_players WHERE tournament_id = t.id) AS players_count`
+
+func scanTournament(row pgx.Row, tournament *models.Tournament) error {
+	return row.Scan(
+		&tournament.ID, &tournament.Title, &tournament.OrganizerID, &tournament.OrganizerName, &tournament.Format,
+		&tournament.Preset, &tournament.RoundMinutes, &tournament.Rounds, &tournament.CurrentRound,
+		&tournament.RoundDeadline, &tournament.Status, &tournament.WinnerID, &tournament.CreatedAt, &tournament.PlayersCount,
+	)
+}
+
+func (s *Storage) CreateTournament(ctx context.Context, tournament *models.Tournament) error {
+	const op = "storage.postgres.CreateTournament"
+
+	_, err := s.DB.Exec(ctx, `
+	INSERT INTO tournaments (id, title, organizer_id, format, preset, round_minutes)
+	VALUES ($1, $2, $3, $4, $5, $6)`,
+		tournament.ID, tournament.Title, tournament.OrganizerID, tournament.Format, tournament.Preset, tournament.RoundMinutes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetTournaments(ctx context.Context) ([]*models.Tournament, error) {
+	const op = "storage.postgres.GetTournaments"
+
+	rows, err := s.DB.Query(ctx, `
+	SELECT `+tournamentColumns+`
+	FROM tournaments t
+	JOIN users u ON u.id = t.organizer_id
+	ORDER BY t.created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	tournaments := make([]*models.Tournament, 0)
+	for rows.Next() {
+		var tournament models.Tournament
+		if err := scanTournament(rows, &tournament); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tournaments = append(tournaments, &tournament)
+	}
+
+	return tournaments, nil
+}
+
+// GetTournament возвращает турнир с участниками в порядке посева и матчами в порядке раундов
+func (s *Storage) GetTournament(ctx context.Context, id string) (*models.TournamentDetails, error) {
+	const op = "storage.postgres.GetTournament"
+
+	var tournament models.TournamentDetails
+	err := scanTournament(s.DB.QueryRow(ctx, `
+	SELECT `+tournamentColumns+`
+	FROM tournaments t
+	JOIN users u ON u.id = t.organizer_id
+	WHERE t.id = $1`, id), &tournament.Tournament)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrTournamentNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	players, err := s.DB.Query(ctx, `
+	SELECT u.id, u.username, tp.seed, tp.wins, tp.draws, tp.losses, tp.byes, tp.eliminated
+	FROM tournament_players tp
+	JOIN users u ON u.id = tp.user_id
+	WHERE tp.tournament_id = $1
+	ORDER BY tp.seed`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer players.Close()
+
+	tournament.Players = make([]*models.TournamentPlayer, 0)
+	for players.Next() {
+		var player models.TournamentPlayer
+		err := players.Scan(&player.ID, &player.Username, &player.Seed, &player.Wins, &player.Draws, &player.Losses, &player.Byes, &player.Eliminated)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tournament.Players = append(tournament.Players, &player)
+	}
+
+	matches, err := s.DB.Query(ctx, `
+	SELECT id, round, game_id, player1_id, player2_id, winner_id, status
+	FROM tournament_matches
+	WHERE tournament_id = $1
+	ORDER BY round, id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer matches.Close()
+
+	tournament.Matches = make([]*models.TournamentMatch, 0)
+	for matches.Next() {
+		var match models.TournamentMatch
+		err := matches.Scan(&match.ID, &match.Round, &match.GameID, &match.Player1ID, &match.Player2ID, &match.WinnerID, &match.Status)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tournament.Matches = append(tournament.Matches, &match)
+	}
+
+	return &tournament, nil
+}
+
+// RegisterTournamentPlayer добавляет участника в открытый турнир, посев равен порядку регистрации
+func (s *Storage) RegisterTournamentPlayer(ctx context.Context, id string, userID int64) (err error) {
+	const op = "storage.postgres.RegisterTournamentPlayer"
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = fmt.Errorf("rollback failed: %v, original error: %w", rollbackErr, err)
+			}
+		} else {
+			if cErr := tx.Commit(ctx); cErr != nil {
+				err = fmt.Errorf("commit failed: %v, original error: %w", cErr, err)
+			}
+		}
+	}()
+
+	var status string
+	err = tx.QueryRow(ctx, "SELECT status FROM tournaments WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return storage.ErrTournamentNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if status != "open" {
+		return storage.ErrTournamentNotOpen
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO tournament_players (tournament_id, user_id, seed)
+	VALUES ($1, $2, (SELECT COUNT(*) + 1 FROM tournament_players WHERE tournament_id = $1))`, id, userID)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return storage.ErrAlreadyRegistered
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// StartTournament закрывает регистрацию и сохраняет количество раундов
+func (s *Storage) StartTournament(ctx context.Context, id string, rounds int) error {
+	const op = "storage.postgres.StartTournament"
+
+	cmd, err := s.DB.Exec(ctx, "UPDATE tournaments SET status = 'started', rounds = $2 WHERE id = $1 AND status = 'open'", id, rounds)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return storage.ErrTournamentNotOpen
+	}
+
+	return nil
+}
+
+// AdvanceTournamentRound переводит турнир из раунда from в раунд to.
+// Возвращает false, если раунд уже сменил кто-то другой
+func (s *Storage) AdvanceTournamentRound(ctx context.Context, id string, from, to int, deadline time.Time) (bool, error) {
+	const op = "storage.postgres.AdvanceTournamentRound"
+
+	cmd, err := s.DB.Exec(ctx, `
+	UPDATE tournaments SET current_round = $3, round_deadline = $4
+	WHERE id = $1 AND current_round = $2 AND status = 'started'`, id, from, to, deadline)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cmd.RowsAffected() != 0, nil
+}
+
+// CloseTournament завершает турнир. Возвращает false, если турнир уже завершён
+func (s *Storage) CloseTournament(ctx context.Context, id string, winnerID int64) (bool, error) {
+	const op = "storage.postgres.CloseTournament"
+
+	cmd, err := s.DB.Exec(ctx, `
+	UPDATE tournaments SET status = 'closed', winner_id = $2, round_deadline = NULL
+	WHERE id = $1 AND status = 'started'`, id, winnerID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cmd.RowsAffected() != 0, nil
+}
+
+func (s *Storage) CreateTournamentMatch(ctx context.Context, tournamentID string, match *models.TournamentMatch) (int64, error) {
+	const op = "storage.postgres.CreateTournamentMatch"
+
+	var id int64
+	err := s.DB.QueryRow(ctx, `
+	INSERT INTO tournament_matches (tournament_id, round, game_id, player1_id, player2_id)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id`, tournamentID, match.Round, match.GameID, match.Player1ID, match.Player2ID).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetTournamentMatchByGame ищет незавершённый матч турнира, сыгранный в игре gameID
+func (s *Storage) GetTournamentMatchByGame(ctx context.Context, gameID string) (string, *models.TournamentMatch, error) {
+	const op = "storage.postgres.GetTournamentMatchByGame"
+
+	var tournamentID string
+	var match models.TournamentMatch
+	err := s.DB.QueryRow(ctx, `
+	SELECT tournament_id, id, round, game_id, player1_id, player2_id, winner_id, status
+	FROM tournament_matches
+	WHERE game_id = $1 AND status = 'pending'`, gameID).
+		Scan(&tournamentID, &match.ID, &match.Round, &match.GameID, &match.Player1ID, &match.Player2ID, &match.WinnerID, &match.Status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil, storage.ErrMatchNotFound
+		}
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tournamentID, &match, nil
+}
+
+// FinishTournamentMatch записывает результат матча и обновляет таблицу участников.
+// winnerID равен nil при ничьей, eliminate выбывает проигравшего
+func (s *Storage) FinishTournamentMatch(ctx context.Context, tournamentID string, match *models.TournamentMatch, winnerID *int64, eliminate bool) (err error) {
+	const op = "storage.postgres.FinishTournamentMatch"
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = fmt.Errorf("rollback failed: %v, original error: %w", rollbackErr, err)
+			}
+		} else {
+			if cErr := tx.Commit(ctx); cErr != nil {
+				err = fmt.Errorf("commit failed: %v, original error: %w", cErr, err)
+			}
+		}
+	}()
+
+	cmd, err := tx.Exec(ctx, "UPDATE tournament_matches SET winner_id = $2, status = 'finished' WHERE id = $1 AND status = 'pending'", match.ID, winnerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return storage.ErrMatchFinished
+	}
+
+	if match.Player2ID == nil {
+		_, err = tx.Exec(ctx, "UPDATE tournament_players SET wins = wins + 1, byes = byes + 1 WHERE tournament_id = $1 AND user_id = $2",
+			tournamentID, match.Player1ID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	}
+
+	if winnerID == nil {
+		_, err = tx.Exec(ctx, "UPDATE tournament_players SET draws = draws + 1 WHERE tournament_id = $1 AND user_id IN ($2, $3)",
+			tournamentID, match.Player1ID, *match.Player2ID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	}
+
+	loserID := match.Player1ID
+	if loserID == *winnerID {
+		loserID = *match.Player2ID
+	}
+	_, err = tx.Exec(ctx, "UPDATE tournament_players SET wins = wins + 1 WHERE tournament_id = $1 AND user_id = $2", tournamentID, *winnerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	_, err = tx.Exec(ctx, "UPDATE tournament_players SET losses = losses + 1, eliminated = $3 WHERE tournament_id = $1 AND user_id = $2",
+		tournamentID, loserID, eliminate)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetExpiredTournaments возвращает идущие турниры, у которых закончилось время текущего раунда
+func (s *Storage) GetExpiredTournaments(ctx context.Context, now time.Time) ([]string, error) {
+	const op = "storage.postgres.GetExpiredTournaments"
+
+	rows, err := s.DB.Query(ctx, "SELECT id FROM tournaments WHERE status = 'started' AND round_deadline < $1", now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -59,3 +59,18 @@ func (s *Storage) GetUserByID(ctx context.Context, id int64) (*models.User, erro
 	}
 	return &user, nil
 }
+
+// GetBotUser возвращает пользователя-бота по имени, обычные пользователи не возвращаются
+func (s *Storage) GetBotUser(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	err := s.DB.QueryRow(ctx, "SELECT id, username FROM users WHERE username = $1 AND is_bot", username).
+		Scan(&user.ID, &user.Username)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, storage.ErrUserNotFound
+		}
+		return nil, err
+	}
+	user.IsBot = true
+	return &user, nil
+}
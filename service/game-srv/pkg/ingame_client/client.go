@@ -1,14 +1,18 @@
 package ingameclient
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"ms4me/game/internal/config"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
 const gameSocketReadyEndpoint = "/api/v1/internal/game/%s/ready"
+const gameSocketOnlineEndpoint = "/api/v1/internal/online"
 
 var (
 	ErrNotReady = errors.New("Не все участники игры готовы")
@@ -49,3 +53,39 @@ func (c *IngameClient) Ready(gameID string) error {
 
 	return nil
 }
+
+type onlineResponse struct {
+	Online []int64 `json:"online"`
+}
+
+// Online возвращает тех из пользователей ids, кто сейчас подключён к ingame-srv по вебсокету
+func (c *IngameClient) Online(ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return []int64{}, nil
+	}
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+	url := *c.URL
+	url.Path = gameSocketOnlineEndpoint
+	url.RawQuery = "ids=" + strings.Join(parts, ",")
+
+	client := &http.Client{}
+
+	resp, err := client.Get(url.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var data onlineResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data.Online, nil
+}
@@ -0,0 +1,55 @@
+import { DailyAttempt, DailyChallenge } from "../models/models";
+import { API_URI, BaseResponse, STATUS_ERROR } from "./api"
+
+export interface DailyResponse extends BaseResponse {
+    daily: DailyChallenge;
+}
+
+export interface PlayDailyResponse extends BaseResponse {
+    id: string;
+}
+
+export interface LeaderboardResponse extends BaseResponse {
+    day: string;
+    leaderboard: Array<DailyAttempt>;
+}
+
+export const getDaily = async () => {
+    const res = await fetch(`${API_URI}/api/v1/daily`, {
+        credentials: "include"
+    });
+    const data: DailyResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+
+    return data.daily;
+}
+
+export const playDaily = async () => {
+    const res = await fetch(`${API_URI}/api/v1/daily/play`, {
+        method: "POST",
+        credentials: "include",
+    })
+    const data: PlayDailyResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+    return data.id;
+}
+
+export const getDailyLeaderboard = async (day?: string) => {
+    const query = day ? `?day=${day}` : "";
+    const res = await fetch(`${API_URI}/api/v1/daily/leaderboard${query}`, {
+        credentials: "include"
+    });
+    const data: LeaderboardResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+
+    return data.leaderboard;
+}
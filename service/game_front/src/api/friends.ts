@@ -0,0 +1,114 @@
+import { DirectMessage, Friend } from "../models/models";
+import { API_URI, BaseResponse, STATUS_ERROR } from "./api"
+
+export interface FriendsResponse extends BaseResponse {
+    friends: Array<Friend>;
+}
+
+export interface AddFriendResponse extends BaseResponse {
+    accepted: boolean;
+}
+
+export interface DirectMessagesResponse extends BaseResponse {
+    messages: Array<DirectMessage>;
+}
+
+export const getFriends = async () => {
+    const res = await fetch(`${API_URI}/api/v1/friend`, {
+        credentials: "include"
+    });
+    const data: FriendsResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+
+    return data.friends;
+}
+
+export const addFriend = async (username: string) => {
+    const res = await fetch(`${API_URI}/api/v1/friend`, {
+        method: "POST",
+        credentials: "include",
+        headers: {
+            "Content-Type": "application/json"
+        },
+        body: JSON.stringify({"username": username})
+    })
+    const data: AddFriendResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+    return data.accepted;
+}
+
+export const acceptFriend = async (id: number) => {
+    const res = await fetch(`${API_URI}/api/v1/friend/${id}/accept`, {
+        method: "POST",
+        credentials: "include",
+    })
+    const data: BaseResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
+
+export const removeFriend = async (id: number) => {
+    const res = await fetch(`${API_URI}/api/v1/friend/${id}`, {
+        method: "DELETE",
+        credentials: "include",
+    })
+    const data: BaseResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
+
+export const getDirectMessages = async (id: number, before?: number) => {
+    const query = before ? `?before=${before}` : "";
+    const res = await fetch(`${API_URI}/api/v1/friend/${id}/messages${query}`, {
+        credentials: "include"
+    });
+    const data: DirectMessagesResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+
+    return data.messages;
+}
+
+export const sendDirectMessage = async (id: number, text: string) => {
+    const res = await fetch(`${API_URI}/api/v1/friend/${id}/messages`, {
+        method: "POST",
+        credentials: "include",
+        headers: {
+            "Content-Type": "application/json"
+        },
+        body: JSON.stringify({"text": text})
+    })
+    const data: BaseResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
+
+export const inviteFriend = async (id: number, gameID: string) => {
+    const res = await fetch(`${API_URI}/api/v1/friend/${id}/invite`, {
+        method: "POST",
+        credentials: "include",
+        headers: {
+            "Content-Type": "application/json"
+        },
+        body: JSON.stringify({"game_id": gameID})
+    })
+    const data: BaseResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
@@ -1,4 +1,4 @@
-import { Game, GameDetails } from "../models/models";
+import { Game, GameDetails, UserStats } from "../models/models";
 import { API_URI, BaseResponse, STATUS_ERROR } from "./api"
 
 export interface GamesResponse extends BaseResponse {
@@ -13,6 +13,10 @@ export interface CreateGameResponse extends BaseResponse {
     id: string;
 }
 
+export interface UserStatsResponse extends BaseResponse {
+    stats: UserStats;
+}
+
 export interface GetCongratulationResponse extends BaseResponse {
     congratulation: string;
 }
@@ -43,14 +47,27 @@ export const getMyGames = async () => {
     return data.games;
 }
 
-export const createGame = async (name: string, isPublic: boolean) => {
+export const getMyStats = async () => {
+    const res = await fetch(`${API_URI}/api/v1/user/stats`, {
+        credentials: "include"
+    });
+    const data: UserStatsResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+
+    return data.stats;
+}
+
+export const createGame = async (name: string, isPublic: boolean, noGuess: boolean, fair: boolean, topology: string, questions: boolean, scoring: boolean, lives: number, coop: boolean, solo: boolean) => {
     const res = await fetch(`${API_URI}/api/v1/game`, {
         method: "POST",
         credentials: "include",
         headers: {
             "Content-Type": "application/json",
         },
-        body: JSON.stringify({"title": name, "is_public": isPublic})
+        body: JSON.stringify({"title": name, "is_public": isPublic, "no_guess": noGuess, "fair": fair, "topology": topology, "questions": questions, "scoring": scoring, "lives": lives, "coop": coop, "solo": solo})
     })
     const data: CreateGameResponse = await res.json();
 
@@ -120,6 +137,21 @@ export const exitGame = async (id: string) => {
     }
 }
 
+export const addBot = async (id: string, level: string) => {
+    const res = await fetch(`${API_URI}/api/v1/game/${id}/bot`, {
+        method: "POST",
+        credentials: "include",
+        headers: {
+            "Content-Type": "application/json",
+        },
+        body: JSON.stringify({"level": level})
+    })
+    const data: BaseResponse = await res.json();
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
+
 export const startGame = async (id: string) => {
     const res = await fetch(`${API_URI}/api/v1/game/${id}/start`, {
         method: "POST",
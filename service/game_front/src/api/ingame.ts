@@ -6,8 +6,15 @@ interface GetGameInfoResponse extends BaseResponse {
     participants: Array<RoomParticipant>;
 }
 
+interface HintResponse extends BaseResponse {
+    row: number;
+    col: number;
+    hints_left: number;
+}
+
 interface GetMessagesResponse extends BaseResponse {
     messages: Array<Message> | null;
+    has_more: boolean;
 }
 
 export const openCell = async (id: string, row: number, col: number) => {
@@ -42,6 +49,35 @@ export const setFlag = async (id: string, row: number, col: number) => {
     }
 }
 
+export const chordCell = async (id: string, row: number, col: number) => {
+    const res = await fetch(`${API_GAME_URI}/api/v1/game/${id}/cell/chord`, {
+        method: "PATCH",
+        credentials: "include",
+        headers: {
+            "Content-Type": "application/json"
+        },
+        body: JSON.stringify({"row": row, "col": col})
+    });
+    const data: BaseResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
+
+export const requestHint = async (id: string) => {
+    const res = await fetch(`${API_GAME_URI}/api/v1/game/${id}/hint`, {
+        method: "POST",
+        credentials: "include"
+    });
+    const data: HintResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+    return data;
+}
+
 export const getGameInfo = async (id: string) => {
     const res = await fetch(`${API_GAME_URI}/api/v1/game/${id}/info`, {
         credentials: "include"
@@ -53,8 +89,9 @@ export const getGameInfo = async (id: string) => {
     return data.participants;
 }
 
-export const getMessages = async (id: string) => {
-    const res = await fetch(`${API_GAME_URI}/api/v1/game/${id}/chat`, {
+export const getMessages = async (id: string, before?: string) => {
+    const params = before ? `?${new URLSearchParams({ before })}` : "";
+    const res = await fetch(`${API_GAME_URI}/api/v1/game/${id}/chat${params}`, {
         credentials: "include"
     })
     const data: GetMessagesResponse = await res.json();
@@ -64,6 +101,32 @@ export const getMessages = async (id: string) => {
     return data.messages ? data.messages : [];
 }
 
+export const editMessage = async (id: string, messageID: string, text: string) => {
+    const res = await fetch(`${API_GAME_URI}/api/v1/game/${id}/chat/${messageID}`, {
+        method: "PATCH",
+        credentials: "include",
+        headers: {
+            "Content-Type": "application/json"
+        },
+        body: JSON.stringify({"text": text})
+    })
+    const data: BaseResponse = await res.json();
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
+
+export const deleteMessage = async (id: string, messageID: string) => {
+    const res = await fetch(`${API_GAME_URI}/api/v1/game/${id}/chat/${messageID}`, {
+        method: "DELETE",
+        credentials: "include"
+    })
+    const data: BaseResponse = await res.json();
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
+
 export const sendMessage = async (id: string, text: string) => {
     const res = await fetch(`${API_GAME_URI}/api/v1/game/${id}/chat`, {
         method: "POST",
@@ -77,4 +140,59 @@ export const sendMessage = async (id: string, text: string) => {
     if (data.status == STATUS_ERROR) {
         throw Error(data.error);
     }
-}
\ No newline at end of file
+}
+export const muteUser = async (id: string, userID: number) => {
+    const res = await fetch(`${API_GAME_URI}/api/v1/game/${id}/chat/mute`, {
+        method: "POST",
+        credentials: "include",
+        headers: {
+            "Content-Type": "application/json"
+        },
+        body: JSON.stringify({"user_id": userID})
+    })
+    const data: BaseResponse = await res.json();
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
+
+export const getLobbyMessages = async () => {
+    const res = await fetch(`${API_GAME_URI}/api/v1/lobby/chat`, {
+        credentials: "include"
+    })
+    const data: GetMessagesResponse = await res.json();
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+    return data.messages ? data.messages : [];
+}
+
+export const sendLobbyMessage = async (text: string) => {
+    const res = await fetch(`${API_GAME_URI}/api/v1/lobby/chat`, {
+        method: "POST",
+        credentials: "include",
+        headers: {
+            "Content-Type": "application/json"
+        },
+        body: JSON.stringify({"text": text})
+    })
+    const data: BaseResponse = await res.json();
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
+
+export const sendEmote = async (id: string, emote: string) => {
+    const res = await fetch(`${API_GAME_URI}/api/v1/game/${id}/emote`, {
+        method: "POST",
+        credentials: "include",
+        headers: {
+            "Content-Type": "application/json"
+        },
+        body: JSON.stringify({"emote": emote})
+    })
+    const data: BaseResponse = await res.json();
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
@@ -0,0 +1,81 @@
+import { Tournament, TournamentDetails } from "../models/models";
+import { API_URI, BaseResponse, STATUS_ERROR } from "./api"
+
+export interface TournamentsResponse extends BaseResponse {
+    tournaments: Array<Tournament>;
+}
+
+export interface TournamentResponse extends BaseResponse {
+    tournament: TournamentDetails;
+}
+
+export interface CreateTournamentResponse extends BaseResponse {
+    id: string;
+}
+
+export const getTournaments = async () => {
+    const res = await fetch(`${API_URI}/api/v1/tournament`, {
+        credentials: "include"
+    });
+    const data: TournamentsResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+
+    return data.tournaments;
+}
+
+export const getTournament = async (id: string) => {
+    const res = await fetch(`${API_URI}/api/v1/tournament/${id}`, {
+        credentials: "include"
+    });
+    const data: TournamentResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+
+    return data.tournament;
+}
+
+export const createTournament = async (title: string, format: string, preset: string, roundMinutes: number) => {
+    const res = await fetch(`${API_URI}/api/v1/tournament`, {
+        method: "POST",
+        credentials: "include",
+        headers: {
+            "Content-Type": "application/json",
+        },
+        body: JSON.stringify({"title": title, "format": format, "preset": preset, "round_minutes": roundMinutes})
+    })
+    const data: CreateTournamentResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+    return data.id;
+}
+
+export const registerTournament = async (id: string) => {
+    const res = await fetch(`${API_URI}/api/v1/tournament/${id}/register`, {
+        method: "POST",
+        credentials: "include",
+    })
+    const data: BaseResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
+
+export const startTournament = async (id: string) => {
+    const res = await fetch(`${API_URI}/api/v1/tournament/${id}/start`, {
+        method: "POST",
+        credentials: "include",
+    })
+    const data: BaseResponse = await res.json();
+
+    if (data.status == STATUS_ERROR) {
+        throw Error(data.error);
+    }
+}
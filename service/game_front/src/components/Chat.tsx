@@ -2,13 +2,16 @@ import { useState, useRef, useEffect, useImperativeHandle, forwardRef } from "re
 import "../styles/Chat.css";
 import { Message } from "../models/models";
 import { useAuth } from "../context/AuthProvider";
-import { sendMessage } from "../api/ingame";
+import { deleteMessage, editMessage, muteUser, sendMessage } from "../api/ingame";
 import { toast } from "react-toastify";
 
 interface Props {
   id: string;
   messages: Message[];
   withInput: boolean;
+  canMute?: boolean;
+  editable?: boolean;
+  send?: (text: string) => Promise<void>;
 }
 
 export const Chat = forwardRef((props: Props, ref) => {
@@ -32,7 +35,7 @@ export const Chat = forwardRef((props: Props, ref) => {
       if (!input.trim()) return;
       
       try {
-        await sendMessage(props.id, input);
+        await (props.send ? props.send(input) : sendMessage(props.id, input));
         setInput("");
       } catch (e: any) {
         toast.error(e.message);
@@ -40,6 +43,33 @@ export const Chat = forwardRef((props: Props, ref) => {
     }
   };
 
+  const handleEdit = async (msg: Message) => {
+    const text = window.prompt("Изменить сообщение", msg.text);
+    if (!text || !text.trim() || text === msg.text) return;
+    try {
+      await editMessage(props.id, msg.id, text);
+    } catch (e: any) {
+      toast.error(e.message);
+    }
+  };
+
+  const handleDelete = async (msg: Message) => {
+    try {
+      await deleteMessage(props.id, msg.id);
+    } catch (e: any) {
+      toast.error(e.message);
+    }
+  };
+
+  const handleMute = async (msg: Message) => {
+    try {
+      await muteUser(props.id, msg.creator_id);
+      toast.info(`${msg.creator_username} больше не может писать в чат`);
+    } catch (e: any) {
+      toast.error(e.message);
+    }
+  };
+
   return (
     <div className="chat-card card h-chat w-chat d-flex flex-column" style={{ borderRadius: 15 }}>
       <div
@@ -54,9 +84,9 @@ export const Chat = forwardRef((props: Props, ref) => {
       <div className="card-body d-flex flex-column flex-grow-1 overflow-hidden px-3 py-2">
         <div className="messages flex-grow-1 d-flex flex-column overflow-auto">
           <div style={{ flexGrow: 1 }} />
-          {props.messages.map((msg, i) => (
+          {props.messages.map((msg) => (
             <div
-              key={i}
+              key={msg.id}
               className={`d-flex flex-column mb-2 ${
                 msg.creator_id === user?.id ? "align-items-end" : "align-items-start"
               }`}
@@ -69,7 +99,17 @@ export const Chat = forwardRef((props: Props, ref) => {
                 style={{ maxWidth: "75%" }}
               >
                 {msg.text}
+                {msg.edited_at && <small className="ms-1 opacity-75">(изменено)</small>}
+              </div>
+              {props.withInput && (props.editable ?? true) && msg.creator_id === user?.id &&
+              <div className="d-flex gap-2 mt-1">
+                <small role="button" className="text-muted" onClick={() => handleEdit(msg)}>изменить</small>
+                <small role="button" className="text-muted" onClick={() => handleDelete(msg)}>удалить</small>
               </div>
+              }
+              {props.canMute && msg.creator_id !== user?.id &&
+              <small role="button" className="text-muted mt-1" onClick={() => handleMute(msg)}>заглушить</small>
+              }
             </div>
           ))}
           <div ref={messagesEndRef} />
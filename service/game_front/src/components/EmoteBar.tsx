@@ -0,0 +1,38 @@
+import { toast } from "react-toastify";
+import { sendEmote } from "../api/ingame";
+
+// Набор эмоций совпадает с разрешёнными в ingame-srv
+export const EMOTES: Record<string, string> = {
+  gg: "🤝",
+  wp: "👏",
+  thanks: "🙏",
+  oops: "😅",
+  wow: "😮",
+  think: "🤔",
+  laugh: "😂",
+  angry: "😠",
+};
+
+interface Props {
+  id: string;
+}
+
+export const EmoteBar = (props: Props) => {
+  const handleClick = async (emote: string) => {
+    try {
+      await sendEmote(props.id, emote);
+    } catch (e: any) {
+      toast.error(e.message);
+    }
+  };
+
+  return (
+    <div className="d-flex flex-wrap gap-1 mb-2">
+      {Object.entries(EMOTES).map(([emote, symbol]) => (
+        <button key={emote} className="btn btn-light btn-sm" title={emote} onClick={() => handleClick(emote)}>
+          {symbol}
+        </button>
+      ))}
+    </div>
+  );
+};
@@ -1,4 +1,4 @@
-export type CellType = "c" | "0" | "f" | "m" | "1" | "2" | "3" | "4" | "5" | "6" | "7" | "8";
+export type CellType = "c" | "0" | "f" | "q" | "m" | "x" | "1" | "2" | "3" | "4" | "5" | "6" | "7" | "8";
 
 export const getCellClass = (type: CellType) => {
   switch(type) {
@@ -8,8 +8,12 @@ export const getCellClass = (type: CellType) => {
       return "btn btn-light";
     case "f":
       return "btn btn-danger";
+    case "q":
+      return "btn btn-warning";
     case "m":
       return "btn btn-dark";
+    case "x":
+      return "btn btn-outline-danger";
     case "1":
       return "btn btn-light text-primary";
     case "2":
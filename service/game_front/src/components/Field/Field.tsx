@@ -1,7 +1,7 @@
 import '../../styles/Minefield.css';
 import { CellType, getCellClass } from './Cell';
 import { RoomParticipant } from '../../models/events';
-import { openCell, setFlag } from '../../api/ingame';
+import { openCell, setFlag, chordCell, requestHint } from '../../api/ingame';
 import { toast } from 'react-toastify';
 import { useAuth } from '../../context/AuthProvider';
 
@@ -31,6 +31,8 @@ export const Field = (props: Props) => {
     try {
       if (e.button === 0) {
         await openCell(props.gameID, row, col);
+      } else if (e.button === 1) {
+        await chordCell(props.gameID, row, col);
       } else if (e.button === 2) {
         await setFlag(props.gameID, row, col);
       }
@@ -39,8 +41,30 @@ export const Field = (props: Props) => {
     }
   };
 
+  const handleHint = async () => {
+    try {
+      const hint = await requestHint(props.gameID);
+      toast.info(`Подсказка: клетка ${hint.row + 1}:${hint.col + 1}, осталось ${hint.hints_left}`);
+    } catch (err: any) {
+      toast.error(err.message);
+    }
+  };
+
   return (
     <div className="container-fluid">
+      {participant && props.fieldOwnerID === user?.id && participant.field &&
+        <button className="btn btn-outline-secondary btn-sm mb-2" onClick={handleHint}>
+          Подсказка{participant.hints_used ? ` (использовано ${participant.hints_used})` : ""}
+        </button>
+      }
+      {participant && participant.field &&
+        <div className="mb-2">
+          💣 {participant.field.mines - (participant.field.flags ?? 0) - (participant.field.exploded ?? 0)}
+          {!!participant.field.lives && <span className="ms-3">❤️ {participant.field.lives}</span>}
+          {participant.score !== undefined && participant.score !== 0 && <span className="ms-3">⭐ {participant.score}</span>}
+          {participant.last_move && <span className="ms-3">Ходил: {props.roomParticipants?.find((val) => val.id == participant?.last_move?.user_id)?.username}</span>}
+        </div>
+      }
       {participant &&
         <div className="minefield d-grid" style={{gridTemplateColumns: `repeat(${participant.field ? participant.field.cols : 8}, 1fr)`, gap: "2px"}}>
         {!participant.field && closedCells.map((type, idx) => (
@@ -55,14 +79,20 @@ export const Field = (props: Props) => {
           row.map((cell, idx2) => (
             <button
             key={`${idx1}_${idx2}`}
-            className={`${getCellClass(cell.value as CellType)} cell`}
+            className={`${getCellClass(cell.value as CellType)} cell${participant?.last_move?.cells.includes(`${idx1}_${idx2}`) ? " border border-info border-2" : ""}`}
+            style={participant?.field?.topology === "hex" && idx1 % 2 === 1 ? {transform: "translateX(50%)"} : undefined}
             onClick={(e) => handleClick(e, idx1, idx2)}
+            onAuxClick={(e) => {
+              if (e.button === 1) handleClick(e, idx1, idx2);
+            }}
             onContextMenu={(e) => {
               e.preventDefault();
               handleClick(e, idx1, idx2);
             }}>
               {(cell.value === "f" && "🚩") ||
+              (cell.value === "q" && "❓") ||
               (cell.value === "m" && "💣") ||
+              (cell.value === "x" && "💥") ||
               (cell.value != "c" && cell.value)}
             </button>
           ))
@@ -0,0 +1,153 @@
+import { useEffect, useState } from "react";
+import { toast } from "react-toastify";
+import { DirectMessage, Friend } from "../models/models";
+import { acceptFriend, addFriend, getDirectMessages, getFriends, removeFriend, sendDirectMessage } from "../api/friends";
+import { useAuth } from "../context/AuthProvider";
+
+interface Props {
+  // последнее личное сообщение, пришедшее по вебсокету лобби
+  lastMessage: DirectMessage | null;
+}
+
+export const FriendList = (props: Props) => {
+  const { user } = useAuth();
+  const [friends, setFriends] = useState<Friend[]>([]);
+  const [username, setUsername] = useState("");
+  const [selected, setSelected] = useState<Friend | null>(null);
+  const [messages, setMessages] = useState<DirectMessage[]>([]);
+  const [input, setInput] = useState("");
+
+  const load = async () => {
+    try {
+      setFriends(await getFriends());
+    } catch (e: any) {
+      toast.error(e.message);
+    }
+  };
+
+  useEffect(() => {
+    if (user === null) return;
+    load();
+  }, []);
+
+  useEffect(() => {
+    const message = props.lastMessage;
+    if (!message) return;
+    if (selected && (message.sender_id == selected.id || message.recipient_id == selected.id)) {
+      setMessages((prev) => [...prev, message]);
+    } else if (message.sender_id != user?.id) {
+      toast.info(`${message.sender_username}: ${message.text}`);
+    }
+  }, [props.lastMessage]);
+
+  const handleAdd = async () => {
+    if (!username.trim()) return;
+    try {
+      const accepted = await addFriend(username);
+      toast.success(accepted ? `${username} теперь в друзьях` : "Заявка в друзья отправлена");
+      setUsername("");
+      load();
+    } catch (e: any) {
+      toast.error(e.message);
+    }
+  };
+
+  const handleAccept = async (friend: Friend) => {
+    try {
+      await acceptFriend(friend.id);
+      load();
+    } catch (e: any) {
+      toast.error(e.message);
+    }
+  };
+
+  const handleRemove = async (friend: Friend) => {
+    try {
+      await removeFriend(friend.id);
+      if (selected?.id == friend.id) setSelected(null);
+      load();
+    } catch (e: any) {
+      toast.error(e.message);
+    }
+  };
+
+  const handleSelect = async (friend: Friend) => {
+    try {
+      setMessages(await getDirectMessages(friend.id));
+      setSelected(friend);
+    } catch (e: any) {
+      toast.error(e.message);
+    }
+  };
+
+  const handleKeyDown = async (e: React.KeyboardEvent) => {
+    if (e.key === "Enter" && !e.shiftKey && selected) {
+      e.preventDefault();
+      if (!input.trim()) return;
+      try {
+        await sendDirectMessage(selected.id, input);
+        setMessages(await getDirectMessages(selected.id));
+        setInput("");
+      } catch (e: any) {
+        toast.error(e.message);
+      }
+    }
+  };
+
+  return (
+    <div className="card mb-3">
+      <div className="card-header fw-bold">Друзья</div>
+      <div className="card-body">
+        <div className="d-flex mb-2">
+          <input
+            className="form-control form-control-sm me-2"
+            placeholder="Имя пользователя"
+            value={username}
+            onChange={(e) => setUsername(e.target.value)}
+          />
+          <button className="btn btn-outline-primary btn-sm text-nowrap" onClick={handleAdd}>Добавить</button>
+        </div>
+        <ul className="list-group mb-2">
+          {friends.map((friend) => (
+            <li key={friend.id} className="list-group-item d-flex justify-content-between align-items-center">
+              <span>
+                {friend.status == "accepted" && <span className={friend.online ? "text-success" : "text-secondary"}>● </span>}
+                {friend.username}
+                {friend.status == "pending" && <small className="text-muted"> {friend.incoming ? "(входящая заявка)" : "(заявка отправлена)"}</small>}
+              </span>
+              <span>
+                {friend.status == "pending" && friend.incoming &&
+                <button className="btn btn-outline-success btn-sm me-1" onClick={() => handleAccept(friend)}>✓</button>}
+                {friend.status == "accepted" &&
+                <button className="btn btn-outline-primary btn-sm me-1" onClick={() => handleSelect(friend)}>✉️</button>}
+                <button className="btn btn-outline-danger btn-sm" onClick={() => handleRemove(friend)}>✕</button>
+              </span>
+            </li>
+          ))}
+        </ul>
+        {selected &&
+        <div>
+          <div className="d-flex justify-content-between align-items-center mb-1">
+            <span className="fw-bold">Переписка с {selected.username}</span>
+            <button className="btn-close" aria-label="Закрыть" onClick={() => setSelected(null)}></button>
+          </div>
+          <div className="border rounded p-2 mb-2" style={{ maxHeight: 240, overflowY: "auto" }}>
+            {messages.map((msg) => (
+              <p key={msg.id} className="mb-1">
+                <span className="fw-bold">{msg.sender_username}: </span>{msg.text}
+              </p>
+            ))}
+          </div>
+          <input
+            className="form-control form-control-sm"
+            placeholder="Сообщение"
+            value={input}
+            onChange={(e) => setInput(e.target.value)}
+            onKeyDown={handleKeyDown}
+          />
+        </div>
+        }
+      </div>
+    </div>
+  );
+};
@@ -14,6 +14,14 @@ export const CreateGameModal = (props: Props) => {
     const modalInstanceRef = useRef<Modal | null>(null);
     const nameInput = useRef<HTMLInputElement>(null);
     const [isPublic, setIsPublic] = useState(false);
+    const [noGuess, setNoGuess] = useState(false);
+    const [fair, setFair] = useState(false);
+    const [topology, setTopology] = useState("square");
+    const [questions, setQuestions] = useState(false);
+    const [scoring, setScoring] = useState(false);
+    const [lives, setLives] = useState(1);
+    const [coop, setCoop] = useState(false);
+    const [solo, setSolo] = useState(false);
     const navigate = useNavigate();
 
     useEffect(() => {
@@ -40,10 +48,18 @@ export const CreateGameModal = (props: Props) => {
         setIsPublic(checked);
     };
 
+    const handleNoGuess = async (event: React.ChangeEvent<HTMLInputElement>) => {
+        setNoGuess(event.target.checked);
+    };
+
+    const handleFair = async (event: React.ChangeEvent<HTMLInputElement>) => {
+        setFair(event.target.checked);
+    };
+
     const handleCreate = async () => {
         if (nameInput.current) {
             try {
-                const id = await createGame(nameInput.current.value, isPublic);
+                const id = await createGame(nameInput.current.value, isPublic, noGuess, fair, topology, questions, scoring && !solo, lives, coop && !solo, solo);
                 toast("Игра создана");
                 navigate("/game/" + id);
             } catch (err: any) {
@@ -84,6 +100,54 @@ export const CreateGameModal = (props: Props) => {
                                 Публичная
                             </label>
                         </div>
+                        <div className="form-check">
+                            <input className="form-check-input" type="checkbox" id="create-game-no-guess" checked={noGuess} onChange={handleNoGuess}/>
+                            <label className="form-check-label" htmlFor="create-game-no-guess">
+                                Без угадывания
+                            </label>
+                        </div>
+                        <div className="form-check">
+                            <input className="form-check-input" type="checkbox" id="create-game-fair" checked={fair} onChange={handleFair}/>
+                            <label className="form-check-label" htmlFor="create-game-fair">
+                                Одинаковые поля
+                            </label>
+                        </div>
+                        <div className="form-check">
+                            <input className="form-check-input" type="checkbox" id="create-game-questions" checked={questions} onChange={(e) => setQuestions(e.target.checked)}/>
+                            <label className="form-check-label" htmlFor="create-game-questions">
+                                Знаки вопроса
+                            </label>
+                        </div>
+                        <div className="form-check">
+                            <input className="form-check-input" type="checkbox" id="create-game-scoring" checked={scoring} onChange={(e) => setScoring(e.target.checked)}/>
+                            <label className="form-check-label" htmlFor="create-game-scoring">
+                                Игра на очки
+                            </label>
+                        </div>
+                        <div className="form-check">
+                            <input className="form-check-input" type="checkbox" id="create-game-coop" checked={coop} onChange={(e) => setCoop(e.target.checked)}/>
+                            <label className="form-check-label" htmlFor="create-game-coop">
+                                Совместная игра
+                            </label>
+                        </div>
+                        <div className="form-check">
+                            <input className="form-check-input" type="checkbox" id="create-game-solo" checked={solo} onChange={(e) => setSolo(e.target.checked)}/>
+                            <label className="form-check-label" htmlFor="create-game-solo">
+                                Одиночная игра
+                            </label>
+                        </div>
+                        <div className="form-floating mt-3">
+                            <select className="form-select" id="create-game-topology" value={topology} onChange={(e) => setTopology(e.target.value)}>
+                                <option value="square">Квадратное</option>
+                                <option value="torus">Тор (края склеены)</option>
+                                <option value="hex">Шестиугольное</option>
+                            </select>
+                            <label htmlFor="create-game-topology">Поле</label>
+                        </div>
+                        <div className="form-floating mt-3">
+                            <input type="number" className="form-control" id="create-game-lives" min={1} max={5} value={lives} onChange={(e) => setLives(Number(e.target.value))}/>
+                            <label htmlFor="create-game-lives">Жизни</label>
+                        </div>
                     </div>
                     <div className="modal-footer">
                         <button
@@ -1,14 +1,16 @@
 import { useNavigate } from "react-router";
-import { Game, Message } from "../../models/models";
+import { DirectMessage, Game, Message } from "../../models/models";
 import { useEffect, useRef, useState } from "react";
 import { getGames, getMyGames } from "../../api/games";
 import { toast } from "react-toastify";
-import { CreateRoomEventType, DeleteRoomEventType, ExitRoomEvent, ExitRoomEventType, JoinRoomEvent, JoinRoomEventType, StartGameEventType, UpdateRoomEventType, WSEvent } from "../../models/events";
+import { CreateRoomEventType, DeleteRoomEventType, ExitRoomEvent, ExitRoomEventType, JoinRoomEvent, JoinRoomEventType, DirectMessageEventType, InviteEvent, InviteEventType, LobbyMessageEventType, StartGameEventType, TournamentRoundEvent, TournamentRoundEventType, UpdateRoomEventType, WSEvent } from "../../models/events";
 import { useAuth } from "../../context/AuthProvider";
 import { getCookie } from "../../utils/utils";
 import { WS_URI } from "../../api/api";
-import { getMessages } from "../../api/ingame";
+import { getLobbyMessages, getMessages, sendLobbyMessage } from "../../api/ingame";
 import { ChatModal } from "./ChatModal";
+import { Chat } from "../Chat";
+import { FriendList } from "../FriendList";
 
 interface Props {
     searchQuery: string;
@@ -27,6 +29,15 @@ export const GameList = (props: Props) => {
     const [messages, setMessages] = useState<Message[]>([]);
     const [clickedID, setClickedID] = useState<string | null>(null);
     const [showModal, setShowModal] = useState<boolean>(false);
+    const [lobbyMessages, setLobbyMessages] = useState<Message[]>([]);
+    const [lastDirectMessage, setLastDirectMessage] = useState<DirectMessage | null>(null);
+
+    useEffect(() => {
+        if (user === null) return;
+        getLobbyMessages()
+            .then(setLobbyMessages)
+            .catch((e: any) => toast.error(e.message));
+    }, []);
 
     useEffect(() => {
         const load = async () => {
@@ -114,6 +125,29 @@ export const GameList = (props: Props) => {
                     return game;
                 }));
                 break;
+            case TournamentRoundEventType:
+                var round = event.payload as TournamentRoundEvent;
+                const pairing = round.pairings.find((p) => user && p.players.includes(user.id));
+                if (!pairing) return;
+                toast.info(`${round.title}: начался раунд ${round.round}`, {
+                    onClick: () => navigate("/game/" + pairing.game_id),
+                    autoClose: false,
+                });
+                break;
+            case LobbyMessageEventType:
+                var message = event.payload as Message;
+                setLobbyMessages((prev) => [...prev, message]);
+                break;
+            case DirectMessageEventType:
+                setLastDirectMessage(event.payload as DirectMessage);
+                break;
+            case InviteEventType:
+                var invite = event.payload as InviteEvent;
+                toast.info(`${invite.sender_username} приглашает в игру «${invite.title}»`, {
+                    onClick: () => navigate("/game/" + invite.game_id),
+                    autoClose: false,
+                });
+                break;
             default:
                 console.error("Неизвестный event_type: " + event.event_type);
                 break;
@@ -188,7 +222,12 @@ export const GameList = (props: Props) => {
 
     const handleClick = async (game: Game) => {
         if (game.status == "closed") {
-            setMessages(await getMessages(game.id));
+            try {
+                setMessages(await getMessages(game.id));
+            } catch (e: any) {
+                toast.error(e.message);
+                return;
+            }
             setClickedID(game.id);
             setShowModal(true);
             return;
@@ -245,6 +284,16 @@ export const GameList = (props: Props) => {
                 </div>
             ))}
             <ChatModal id={clickedID} messages={messages} show={showModal} setShow={setShowModal}></ChatModal>
+            {!props.showMyGames &&
+                <div className="m-1 mt-3">
+                    <Chat messages={lobbyMessages} id="lobby" withInput={true} editable={false} send={sendLobbyMessage}/>
+                </div>
+            }
+            {!props.showMyGames &&
+                <div className="m-1 mt-3">
+                    <FriendList lastMessage={lastDirectMessage}/>
+                </div>
+            }
         </>
     );
 }
@@ -0,0 +1,41 @@
+import { useEffect, useState } from "react";
+import { toast } from "react-toastify";
+import { Friend } from "../models/models";
+import { getFriends, inviteFriend } from "../api/friends";
+
+interface Props {
+  gameID: string;
+}
+
+// InviteFriends список друзей с кнопкой приглашения в ещё не начатую игру
+export const InviteFriends = (props: Props) => {
+  const [friends, setFriends] = useState<Friend[]>([]);
+
+  useEffect(() => {
+    getFriends()
+      .then((friends) => setFriends(friends.filter((friend) => friend.status == "accepted")))
+      .catch((e: any) => toast.error(e.message));
+  }, []);
+
+  const handleInvite = async (friend: Friend) => {
+    try {
+      await inviteFriend(friend.id, props.gameID);
+      toast.success(`Приглашение отправлено ${friend.username}`);
+    } catch (e: any) {
+      toast.error(e.message);
+    }
+  };
+
+  if (friends.length == 0) return null;
+
+  return (
+    <div className="d-flex flex-wrap align-items-center gap-1 mt-2">
+      <span className="me-1">Пригласить:</span>
+      {friends.map((friend) => (
+        <button key={friend.id} className="btn btn-outline-secondary btn-sm" onClick={() => handleInvite(friend)}>
+          <span className={friend.online ? "text-success" : "text-secondary"}>● </span>{friend.username}
+        </button>
+      ))}
+    </div>
+  );
+};
@@ -1,3 +1,4 @@
+import { CellType } from "../components/Field/Cell";
 import { Field } from "./field";
 import { Game } from "./models";
 
@@ -11,9 +12,19 @@ export const StartGameEventType = "START_GAME";
 export const OpenCellEventType = "OPEN_CELL";
 export const LoseGameEventType = "LOSE_GAME";
 export const WinGameEventType = "WIN_GAME";
+export const CommitFieldEventType = "FIELD_COMMIT";
 export const NewMessageEventType = "NEW_MESSAGE";
+export const MessageEditedEventType = "MESSAGE_EDITED";
+export const MessageDeletedEventType = "MESSAGE_DELETED";
+export const LobbyMessageEventType = "LOBBY_MESSAGE";
+export const EmoteEventType = "EMOTE";
+export const DirectMessageEventType = "DIRECT_MESSAGE";
+export const InviteEventType = "INVITE";
+export const TournamentRoundEventType = "TOURNAMENT_ROUND";
+export const ResyncEventType = "RESYNC";
 
 export interface WSEvent {
+    seq?: number; // порядковый номер события в комнате
     status: string;
     error?: string;
     event_type: string;
@@ -21,6 +32,20 @@ export interface WSEvent {
     message?: string;
 }
 
+export interface EmoteEvent {
+    user_id: number;
+    username: string;
+    emote: string;
+}
+
+export interface InviteEvent {
+    game_id: string;
+    title: string;
+    sender_id: number;
+    sender_username: string;
+    recipient_id: number;
+}
+
 export interface CreateRoomEvent {
     game: Game;
 }
@@ -35,6 +60,19 @@ export interface DeleteRoomEvent {
     user_id: number;
 }
 
+export interface TournamentPairing {
+    game_id: string;
+    players: Array<number>;
+}
+
+export interface TournamentRoundEvent {
+    tournament_id: string;
+    title: string;
+    round: number;
+    deadline: string;
+    pairings: Array<TournamentPairing>;
+}
+
 export interface JoinRoomEvent {
     id: string;
     user_id: number;
@@ -51,21 +89,69 @@ export interface RoomParticipant {
     id: number;
     username: string;
     is_owner: boolean;
+    moves?: number;
+    hints_used?: number;
+    penalty_until?: string;
+    bot?: string;
+    score?: number;
+    started_at?: string;
+    cells_opened?: number;
+    last_move?: LastMove; // последний ход на общем поле, заполняется клиентом для подсветки
     field: Field | null;
 }
 
+export interface CellChange {
+    row: number;
+    col: number;
+    value: CellType;
+}
+
+export interface FieldDiff {
+    changes: Array<CellChange>;
+    cells_open: number;
+    mine_is_open: boolean;
+    flags: number;
+    score: number;
+    lives: number;
+    exploded: number;
+    moved_by?: number;
+}
+
+export interface LastMove {
+    user_id: number;
+    cells: Array<string>;
+}
+
 export interface ClickGameEvent {
     id: string;
     user_id: number;
-    participants: Array<RoomParticipant>;
+    participants?: Array<RoomParticipant>; // полное состояние полей
+    diffs?: Record<string, FieldDiff>; // изменения полей по id участника
+}
+
+export interface CommitFieldEvent {
+    user_id: number;
+    commitment: string;
+}
+
+export interface FieldReveal {
+    seed: string;
+    origin: { row: number; col: number };
+    layout: string;
+    commitment: string;
 }
 
 export interface LoseGameEvent {
     loser_id: number;
     loser_username: string;
+    reveals?: Record<string, FieldReveal>; // раскрытие полей по id участника
+    team?: boolean; // совместная игра, результат общий
 }
 
 export interface WinGameEvent {
     winner_id: number;
     winner_username: string;
+    reveals?: Record<string, FieldReveal>; // раскрытие полей по id участника
+    scores?: Record<string, number>; // счёт по id участника в игре на очки
+    team?: boolean; // совместная игра, результат общий
 }
\ No newline at end of file
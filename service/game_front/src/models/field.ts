@@ -12,4 +12,9 @@ export interface Field {
     cells_open: number;
     mine_is_open: number;
     grid: Array<Array<Cell>>;
+    topology?: string;
+    flags?: number;
+    questions?: boolean;
+    lives?: number;
+    exploded?: number;
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 export interface User {
     id: number;
     username: string;
+    is_bot?: boolean;
 }
 
 export interface Game {
@@ -13,6 +14,14 @@ export interface Game {
     winner_id: number;
     owner_name: string;
     is_public: boolean;
+    no_guess: boolean;
+    fair: boolean;
+    topology?: string;
+    questions?: boolean;
+    scoring?: boolean;
+    lives?: number;
+    coop?: boolean;
+    solo?: boolean;
     created_at: string;
     status: string;
     players_count: number;
@@ -29,4 +38,96 @@ export interface Message {
   creator_username: string;
   text: string;
   created_at: string;
-};
\ No newline at end of file
+  edited_at?: string;
+  deleted?: boolean;
+};
+export interface Tournament {
+    id: string;
+    title: string;
+    organizer_id: number;
+    organizer_name?: string;
+    format: string;
+    preset: string;
+    round_minutes: number;
+    rounds: number;
+    current_round: number;
+    round_deadline: string | null;
+    status: string;
+    winner_id: number | null;
+    created_at: string;
+    players_count: number;
+}
+
+export interface TournamentPlayer {
+    id: number;
+    username: string;
+    seed: number;
+    wins: number;
+    draws: number;
+    losses: number;
+    byes: number;
+    eliminated: boolean;
+}
+
+export interface TournamentMatch {
+    id: number;
+    round: number;
+    game_id: string | null;
+    player1_id: number;
+    player2_id: number | null;
+    winner_id: number | null;
+    status: string;
+}
+
+export interface TournamentDetails extends Tournament {
+    players: Array<TournamentPlayer>;
+    matches: Array<TournamentMatch>;
+}
+
+export interface DailyAttempt {
+    user_id: number;
+    username: string;
+    game_id: string | null;
+    started_at: string;
+    finished_at: string | null;
+    won: boolean;
+    moves: number;
+    duration_ms: number | null;
+}
+
+export interface DailyChallenge {
+    day: string;
+    rows: number;
+    cols: number;
+    mines: number;
+    topology: string;
+    players: number;
+    winners: number;
+    attempt?: DailyAttempt;
+}
+
+export interface UserStats {
+    played: number;
+    won: number;
+    solo_played: number;
+    solo_won: number;
+    moves: number;
+    cells_opened: number;
+}
+
+export interface Friend {
+    id: number;
+    username: string;
+    status: string;
+    incoming?: boolean;
+    online: boolean;
+}
+
+export interface DirectMessage {
+    id: number;
+    sender_id: number;
+    sender_username: string;
+    recipient_id: number;
+    text: string;
+    created_at: string;
+}
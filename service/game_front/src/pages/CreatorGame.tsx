@@ -1,8 +1,10 @@
 import { useState } from "react";
 import { Field } from "../components/Field/Field";
 import { Chat } from "../components/Chat";
+import { EmoteBar } from "../components/EmoteBar";
+import { InviteFriends } from "../components/InviteFriends";
 import { GameDetails, Message } from "../models/models";
-import { deleteGame, startGame } from "../api/games";
+import { addBot, deleteGame, startGame } from "../api/games";
 import { UpdateGameModal } from "../components/UpdateGameModal";
 import { toast } from "react-toastify";
 import { RoomDetail } from "../components/RoomDetail";
@@ -21,6 +23,7 @@ interface Props {
 
 export const CreatorGame = (props: Props) => {
     const [updateModalShow, setUpdateModalShow] = useState(false);
+    const [botLevel, setBotLevel] = useState("medium");
     const navigate = useNavigate();
     const { user } = useAuth();
 
@@ -44,6 +47,14 @@ export const CreatorGame = (props: Props) => {
         }
     }
 
+    const addBotHandler = async () => {
+        try {
+            await addBot(props.id, botLevel);
+        } catch (e: any) {
+            toast.error(e.message);
+        }
+    }
+
     return (
         <div className="container-fluid d-flex flex-column min-vh-100">
             <div className="d-flex justify-content-between align-items-center mt-4 mb-3">
@@ -61,6 +72,20 @@ export const CreatorGame = (props: Props) => {
                 <button className="btn btn-primary me-2" onClick={startGameHandler}>▶️</button>
                 <button className="btn btn-orange me-2" onClick={() => setUpdateModalShow(true)}>✏️</button>
                 <button className="btn btn-red me-2" onClick={deleteGameHandler}>❌</button>
+                { props.gameInfo.status == "open" && props.gameInfo.players.length < props.gameInfo.max_players &&
+                <span className="d-inline-flex align-items-center me-2">
+                    <select className="form-select form-select-sm me-2" value={botLevel} onChange={(e) => setBotLevel(e.target.value)}>
+                        <option value="easy">Лёгкий</option>
+                        <option value="medium">Средний</option>
+                        <option value="hard">Сложный</option>
+                    </select>
+                    <button className="btn btn-outline-primary btn-sm text-nowrap" onClick={addBotHandler}>Добавить бота</button>
+                </span>
+                }
+
+                { props.gameInfo.status == "open" &&
+                <InviteFriends gameID={props.id}/>
+                }
 
                 <RoomDetail gameInfo={props.gameInfo}></RoomDetail>
             </div>
@@ -72,13 +97,15 @@ export const CreatorGame = (props: Props) => {
                 </div>
                 <div className="col-4">
                     {
-                        (props.gameInfo.players.length > 1 &&
+                        // В совместной игре общее поле одно, второе поле не показывается
+                        !props.gameInfo.coop && ((props.gameInfo.players.length > 1 &&
                         <Field roomParticipants={props.roomParticipants} gameID={props.gameInfo.id} fieldOwnerID={props.gameInfo.players[1].id}/>) ||
-                        <Field roomParticipants={props.roomParticipants} gameID={props.gameInfo.id} fieldOwnerID={null}/>
+                        <Field roomParticipants={props.roomParticipants} gameID={props.gameInfo.id} fieldOwnerID={null}/>)
                     }
                 </div>
                 <div className="col-4">
-                    <Chat messages={props.messages} id={props.id} withInput={true}/>
+                    <EmoteBar id={props.id}/>
+                    <Chat messages={props.messages} id={props.id} withInput={true} canMute={true}/>
                 </div>
             </div>
             <UpdateGameModal id={props.id} show={updateModalShow} setShow={setUpdateModalShow} gameInfo={props.gameInfo}></UpdateGameModal>
@@ -6,11 +6,12 @@ import { enterGame, getCongratulation, getGameByID } from "../api/games";
 import { GameDetails, Message } from "../models/models";
 import { useAuth } from "../context/AuthProvider";
 import { ParticipantGame } from "./ParticipantGame";
-import { ClickGameEvent, DeleteRoomEvent, DeleteRoomEventType, ExitRoomEvent, ExitRoomEventType, JoinRoomEvent, JoinRoomEventType, LoseGameEvent, LoseGameEventType, NewMessageEventType, OpenCellEventType, RoomParticipant, StartGameEventType, UpdateRoomEvent, UpdateRoomEventType, WinGameEvent, WinGameEventType, WSEvent } from "../models/events";
+import { ClickGameEvent, CommitFieldEvent, CommitFieldEventType, DeleteRoomEvent, FieldReveal, DeleteRoomEventType, EmoteEvent, EmoteEventType, ExitRoomEvent, ExitRoomEventType, JoinRoomEvent, JoinRoomEventType, LoseGameEvent, LoseGameEventType, MessageDeletedEventType, MessageEditedEventType, NewMessageEventType, OpenCellEventType, ResyncEventType, RoomParticipant, StartGameEventType, UpdateRoomEvent, UpdateRoomEventType, WinGameEvent, WinGameEventType, WSEvent } from "../models/events";
 import { toast } from "react-toastify";
-import { gameContainsUserID, getCookie } from "../utils/utils";
+import { applyFieldDiffs, gameContainsUserID, getCookie, verifyReveals } from "../utils/utils";
 import { WS_URI } from "../api/api";
 import { getGameInfo, getMessages } from "../api/ingame";
+import { EMOTES } from "../components/EmoteBar";
 
 export const GameDetail = () => {
     const { id } = useParams<{ id: string }>();
@@ -21,8 +22,20 @@ export const GameDetail = () => {
     const wsRef = useRef<WebSocket | null>(null);
     const reconnectRef = useRef<number | null>(null);
     const isActiveRef = useRef(true);
+    const lastSeqRef = useRef<number | null>(null);
     const [isStart, setIsStart] = useState(false);
     const [roomParticipants, setRoomParticipants] = useState<Array<RoomParticipant> | null>(null);
+    const commitmentsRef = useRef<Record<string, string>>({});
+
+    const checkReveals = async (reveals?: Record<string, FieldReveal>) => {
+        if (!reveals) return;
+        const ok = await verifyReveals(commitmentsRef.current, reveals);
+        if (ok === true) {
+            toast.info("Поля проверены: расположение мин совпадает с опубликованным", {autoClose: 5000});
+        } else if (ok === false) {
+            toast.error("Расположение мин не совпадает с опубликованным!", {autoClose: 10000});
+        }
+    };
 
     const eventHandler = async (event: WSEvent) => {
         if (!event.payload) return;
@@ -99,11 +112,22 @@ export const GameDetail = () => {
             break;
         case OpenCellEventType:
             eventData = event.payload as ClickGameEvent;
-            setRoomParticipants(eventData.participants);
+            if (eventData.participants) {
+                setRoomParticipants(eventData.participants);
+            } else if (eventData.diffs) {
+                const diffs = eventData.diffs;
+                setRoomParticipants((prev) => prev ? applyFieldDiffs(prev, diffs) : prev);
+            }
+            break;
+        case CommitFieldEventType:
+            eventData = event.payload as CommitFieldEvent;
+            commitmentsRef.current[eventData.user_id.toString()] = eventData.commitment;
             break;
         case LoseGameEventType:
             eventData = event.payload as LoseGameEvent;
-            if (id && eventData.loser_id != user?.id) {
+            checkReveals(eventData.reveals);
+            // В совместной игре подрыв общего поля - поражение всей команды
+            if (id && !eventData.team && eventData.loser_id != user?.id) {
                 toast.success(await getCongratulation(id), {
                     position: "top-center",
                     autoClose: 10000,
@@ -135,7 +159,11 @@ export const GameDetail = () => {
             break;
         case WinGameEventType:
             eventData = event.payload as WinGameEvent;
-            if (id && eventData.winner_id == user?.id) {
+            checkReveals(eventData.reveals);
+            if (eventData.scores) {
+                toast.info(`Победил ${eventData.winner_username} со счётом ${eventData.scores[String(eventData.winner_id)] ?? 0}`);
+            }
+            if (id && (eventData.team || eventData.winner_id == user?.id)) {
                 toast.success(await getCongratulation(id), {
                     position: "top-center",
                     autoClose: 10000,
@@ -172,6 +200,18 @@ export const GameDetail = () => {
                     return [...prevMessages, eventData];
             });
     break;
+        case EmoteEventType:
+            eventData = event.payload as EmoteEvent;
+            toast(`${eventData.username}: ${EMOTES[eventData.emote] ?? eventData.emote}`, {autoClose: 2000, hideProgressBar: true});
+            break;
+        case MessageEditedEventType:
+            eventData = event.payload as Message;
+            setMessages(prevMessages => prevMessages?.map(msg => msg.id === eventData.id ? eventData : msg));
+            break;
+        case MessageDeletedEventType:
+            eventData = event.payload as Message;
+            setMessages(prevMessages => prevMessages?.filter(msg => msg.id !== eventData.id));
+            break;
         default:
             console.error("Неизвестный event_type: " + event.event_type);
             break;
@@ -189,6 +229,18 @@ export const GameDetail = () => {
             }
         }
     
+    // resync заново загружает состояние игры, если сервер уже не может дослать пропущенные события
+    const resync = async () => {
+        if (!id) return;
+        try {
+            setGame(await getGameByID(id));
+            setRoomParticipants(await getGameInfo(id));
+            setMessages(await getMessages(id));
+        } catch (e: any) {
+            toast.error(e.message);
+        }
+    };
+
     const connectWS = () => {
         const token = getCookie("token");
         if (!user || !token || !isActiveRef.current) return;
@@ -200,13 +252,24 @@ export const GameDetail = () => {
 
         ws.onopen = () => {
             console.log("WebSocket открыт");
-            ws.send(JSON.stringify({ token }));
+            // после переподключения сервер досылает события, пропущенные с last_seq
+            const lastSeq = lastSeqRef.current;
+            ws.send(JSON.stringify(lastSeq === null ? { token } : { token, last_seq: lastSeq }));
         };
 
         ws.onmessage = (ev) => {
             if (ev.data == "ping") return;
             try {
                 const data: WSEvent = JSON.parse(ev.data);
+                if (data.event_type == ResyncEventType) {
+                    resync();
+                    return;
+                }
+                if (data.seq) {
+                    // событие могло прийти и повтором, и вживую
+                    if (lastSeqRef.current !== null && data.seq <= lastSeqRef.current) return;
+                    lastSeqRef.current = data.seq;
+                }
                 eventHandler(data);
             } catch (err) {
                 console.error("Ошибка парсинга:", err);
@@ -2,12 +2,25 @@ import { useState } from "react";
 import { GameList } from "../components/GameList/GameList";
 import { CreateGameModal } from "../components/GameList/CreateGameModal";
 import { useAuth } from "../context/AuthProvider";
+import { playDaily } from "../api/daily";
+import { toast } from "react-toastify";
+import { useNavigate } from "react-router";
 
 export const List = () => {
   const [createModalShow, setCreateModalShow] = useState(false);
   const [searchQuery, setSearchQuery] = useState("");
   const [showMyGames, setShowMyGames] = useState(false);
   const { logout } = useAuth();
+  const navigate = useNavigate();
+
+  const handleDaily = async () => {
+    try {
+      const id = await playDaily();
+      navigate("/game/" + id);
+    } catch (err: any) {
+      toast.error(err.message);
+    }
+  };
 
   return (
     <div className="container-sm">
@@ -26,6 +39,9 @@ export const List = () => {
         >
           Все игры
         </button>
+        <button className="btn btn-outline-success me-3" onClick={handleDaily}>
+          Испытание дня
+        </button>
         <button className="btn btn-red float-end" onClick={logout}>
           Выйти
         </button>
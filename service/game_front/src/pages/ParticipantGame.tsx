@@ -1,5 +1,6 @@
 import { Field } from "../components/Field/Field";
 import { Chat } from "../components/Chat";
+import { EmoteBar } from "../components/EmoteBar";
 import { GameDetails, Message } from "../models/models";
 import { RoomDetail } from "../components/RoomDetail";
 import { exitGame } from "../api/games";
@@ -56,12 +57,14 @@ export const ParticipantGame = (props: Props) => {
             </div>
             <div className="col-4">
                 {
-                    (props.gameInfo.players.length > 1 &&
+                    // В совместной игре общее поле одно, второе поле не показывается
+                    !props.gameInfo.coop && ((props.gameInfo.players.length > 1 &&
                     <Field roomParticipants={props.roomParticipants} gameID={props.gameInfo.id} fieldOwnerID={props.gameInfo.owner_id}/>) ||
-                    <Field roomParticipants={props.roomParticipants} gameID={props.gameInfo.id} fieldOwnerID={null}/>
+                    <Field roomParticipants={props.roomParticipants} gameID={props.gameInfo.id} fieldOwnerID={null}/>)
                 }
             </div>
             <div className="col-4">
+                <EmoteBar id={props.id}/>
                 <Chat messages={props.messages} id={props.id} withInput={true}/>
             </div>
             </div>
@@ -1,3 +1,4 @@
+import { FieldDiff, FieldReveal, RoomParticipant } from "../models/events";
 import { GameDetails } from "../models/models";
 
 export function getCookie(name: string) {
@@ -30,4 +31,51 @@ export function gameContainsUserID(game: GameDetails, userID: number) {
         }
     }
     return false
-}
\ No newline at end of file
+}
+
+// applyFieldDiffs применяет изменения клеток из события OPEN_CELL к полям участников
+export function applyFieldDiffs(participants: Array<RoomParticipant>, diffs: Record<string, FieldDiff>) {
+    return participants.map((participant) => {
+        const diff = diffs[participant.id.toString()];
+        if (!diff || !participant.field) return participant;
+
+        const grid = participant.field.grid.map((row) => row.slice());
+        for (const change of diff.changes) {
+            grid[change.row][change.col] = {
+                value: change.value,
+                is_open: change.value !== "c" && change.value !== "f" && change.value !== "q",
+            };
+        }
+        return {
+            ...participant,
+            score: diff.score,
+            last_move: diff.moved_by ? {
+                user_id: diff.moved_by,
+                cells: diff.changes.map((change) => `${change.row}_${change.col}`),
+            } : participant.last_move,
+            field: {
+                ...participant.field,
+                cells_open: diff.cells_open,
+                flags: diff.flags,
+                lives: diff.lives,
+                exploded: diff.exploded,
+                mine_is_open: Number(diff.mine_is_open),
+                grid: grid,
+            },
+        };
+    });
+}
+
+// verifyReveals проверяет, что раскрытые поля совпадают с commitment, опубликованными при их генерации.
+// Возвращает null, если проверка недоступна (crypto.subtle есть только в защищённом контексте)
+export async function verifyReveals(commitments: Record<string, string>, reveals: Record<string, FieldReveal>) {
+    if (!window.crypto?.subtle) return null;
+    for (const [userID, reveal] of Object.entries(reveals)) {
+        const data = new TextEncoder().encode(`${reveal.seed}|${reveal.origin.row},${reveal.origin.col}|${reveal.layout}`);
+        const hash = await window.crypto.subtle.digest("SHA-256", data);
+        const hex = Array.from(new Uint8Array(hash)).map((b) => b.toString(16).padStart(2, "0")).join("");
+        const committed = commitments[userID] ?? reveal.commitment;
+        if (hex !== reveal.commitment || hex !== committed) return false;
+    }
+    return true;
+}
@@ -7,7 +7,10 @@ import (
 	"ms4me/game_socket/internal/config"
 	"ms4me/game_socket/internal/http/handlers"
 	storage "ms4me/game_socket/internal/redis"
+	"ms4me/game_socket/internal/service/bot"
+	"ms4me/game_socket/internal/service/chat"
 	"ms4me/game_socket/internal/service/eventloop"
+	"ms4me/game_socket/internal/service/play"
 	ws "ms4me/game_socket/internal/ws/server"
 	gameclient "ms4me/game_socket/pkg/game_client"
 	"os"
@@ -31,17 +34,23 @@ func main() {
 	}
 	log := slog.New(prettylogger.NewColoredHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
 
-	redisCli, err := storage.New(appCtx, cfg.RedisConfig, cfg.MessageTTL)
+	redisCli, err := storage.New(appCtx, cfg.RedisConfig, cfg.MessageTTL, cfg.EventsBuffer)
 	if err != nil {
 		panic("error connecting to redis: " + err.Error())
 	}
 	wsSrv := ws.New(log, cfg.AppConfig, redisCli)
-	eventLoop := eventloop.New(log, wsSrv, redisCli)
-	go eventLoop.EventLoop()
 
 	gameClient := gameclient.New(cfg.GameConfig)
-	h := handlers.New(log, redisCli, wsSrv, gameClient)
-	application := app.New(log, cfg.AppConfig, wsSrv, h, gameClient)
+	playSrv := play.New(log, redisCli, gameClient)
+	bots := bot.New(log, redisCli, playSrv, gameClient)
+	chatArchiver := chat.New(log, redisCli, gameClient, cfg.ChatArchive)
+	go chatArchiver.Run()
+	eventLoop := eventloop.New(log, wsSrv, redisCli, bots, chatArchiver, playSrv)
+	go eventLoop.EventLoop()
+
+	moderator := chat.NewModerator(redisCli, chat.NewBannedWords(cfg.BannedWords))
+	h := handlers.New(log, redisCli, wsSrv, gameClient, playSrv, moderator)
+	application := app.New(log, cfg.AppConfig, wsSrv, h, gameClient, redisCli)
 
 	log.Info("starting application", slog.Any("config", cfg))
 	go application.Run()
@@ -53,5 +62,6 @@ func main() {
 
 	log.Info("stopping application", slog.String("signal", stopSignal.String()))
 	eventLoop.Stop()
+	chatArchiver.Stop()
 	application.Stop(appCtx)
 }
@@ -6,6 +6,7 @@ import (
 	"ms4me/game_socket/internal/config"
 	"ms4me/game_socket/internal/http/handlers"
 	"ms4me/game_socket/internal/http/middlewares"
+	storage "ms4me/game_socket/internal/redis"
 	ws "ms4me/game_socket/internal/ws/server"
 	gameclient "ms4me/game_socket/pkg/game_client"
 	"net"
@@ -26,15 +27,17 @@ type App struct {
 	wsSrv      *ws.Server
 	h          *handlers.Handlers
 	gameClient *gameclient.GameClient
+	redis      *storage.Redis
 }
 
-func New(log *slog.Logger, cfg *config.AppConfig, wsSrv *ws.Server, h *handlers.Handlers, gameClient *gameclient.GameClient) *App {
+func New(log *slog.Logger, cfg *config.AppConfig, wsSrv *ws.Server, h *handlers.Handlers, gameClient *gameclient.GameClient, redis *storage.Redis) *App {
 	app := &App{
 		cfg:        cfg,
 		log:        log,
 		wsSrv:      wsSrv,
 		h:          h,
 		gameClient: gameClient,
+		redis:      redis,
 	}
 	app.httpServer = &http.Server{
 		Addr:         net.JoinHostPort(app.cfg.Host, strconv.Itoa(app.cfg.Port)),
@@ -63,7 +66,7 @@ func (a *App) Stop(ctx context.Context) {
 func (a *App) initRouter() *chi.Mux {
 	router := chi.NewRouter()
 
-	m := middlewares.New(a.log, []byte(a.cfg.JwtSecret), a.gameClient)
+	m := middlewares.New(a.log, []byte(a.cfg.JwtSecret), a.gameClient, a.redis)
 
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.RequestID)
@@ -83,26 +86,44 @@ func (a *App) initRouter() *chi.Mux {
 
 	router.Route("/api/v1/internal", func(r chi.Router) {
 		r.Get("/game/{id}/ready", a.h.Ready())
+		r.Get("/online", a.h.Online())
 	})
 
 	router.Route("/api/v1/game", func(gameRouter chi.Router) {
 		gameRouter.Use(m.Auth())
 
-		gameRouter.Get("/{id}/info", a.h.GetGameInfo())
+		gameRouter.With(m.RoomMember()).Get("/{id}/info", a.h.GetGameInfo())
+		gameRouter.With(m.RoomMember()).Post("/{id}/emote", a.h.Emote())
 
 		gameRouter.Route("/{id}", func(r chi.Router) {
+			r.Use(m.RoomMember())
 			r.Use(m.CheckGameStarted())
 
 			r.Patch("/cell/open", a.h.OpenCell())
 			r.Patch("/cell/flag", a.h.Flag())
+			r.Patch("/cell/chord", a.h.Chord())
+			r.Post("/hint", a.h.Hint())
 		})
 
 		gameRouter.Route("/{id}/chat", func(chatRouter chi.Router) {
+			chatRouter.Use(m.RoomMember())
+
 			chatRouter.Get("/", a.h.GetMessages())
 			chatRouter.Post("/", a.h.CreateMessage())
+			chatRouter.Post("/mute", a.h.Mute())
+			chatRouter.Post("/unmute", a.h.Unmute())
+			chatRouter.Patch("/{messageID}", a.h.EditMessage())
+			chatRouter.Delete("/{messageID}", a.h.DeleteMessage())
 		})
 	})
 
+	router.Route("/api/v1/lobby", func(lobbyRouter chi.Router) {
+		lobbyRouter.Use(m.Auth())
+
+		lobbyRouter.Get("/chat", a.h.GetLobbyMessages())
+		lobbyRouter.Post("/chat", a.h.CreateLobbyMessage())
+	})
+
 	router.Handle("/ws", websocket.Handler(a.wsSrv.Handle))
 	router.Handle("/ws/{id}", websocket.Handler(a.wsSrv.Handle))
 
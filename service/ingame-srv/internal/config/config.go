@@ -27,6 +27,9 @@ type AppConfig struct {
 	CORSOrigins   []string      `envconfig:"APP_CORS_ORIGINS"`
 	CORSMethods   []string      `envconfig:"APP_CORS_METHODS"`
 	MessageTTL    time.Duration `envconfig:"APP_MESSAGE_TTL"`
+	EventsBuffer  int           `envconfig:"APP_EVENTS_BUFFER"`
+	ChatArchive   time.Duration `envconfig:"APP_CHAT_ARCHIVE_INTERVAL"`
+	BannedWords   []string      `envconfig:"APP_CHAT_BANNED_WORDS"`
 }
 
 type RedisConfig struct {
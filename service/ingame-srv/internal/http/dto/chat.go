@@ -1,12 +1,67 @@
 package dto
 
-import "ms4me/game_socket/internal/models"
+import (
+	"errors"
+	"ms4me/game_socket/internal/models"
+	"net/url"
+	"strconv"
+)
+
+// defaultMessagesLimit сколько сообщений отдаётся, если limit не указан
+const defaultMessagesLimit = 50
+
+// maxMessagesLimit наибольший размер страницы чата
+const maxMessagesLimit = 100
+
+var (
+	ErrMessagesLimit   = errors.New("limit должен быть числом от 1 до 100")
+	ErrMessagesCursors = errors.New("нельзя указывать before и after одновременно")
+)
 
 type CreateMessageRequest struct {
 	Text string `json:"text" validate:"required,max=256"`
 }
 
+type MuteRequest struct {
+	UserID int64 `json:"user_id" validate:"required"`
+}
+
+type EditMessageRequest struct {
+	Text string `json:"text" validate:"required,max=256"`
+}
+
+// ReadMessagesRequest курсорная страница чата: before и after - id сообщений, от которых листается чат
+type ReadMessagesRequest struct {
+	Before string
+	After  string
+	Limit  int
+}
+
+func (rmr *ReadMessagesRequest) Render(values url.Values) error {
+	rmr.Before, rmr.After = values.Get("before"), values.Get("after")
+	if rmr.Before != "" && rmr.After != "" {
+		return ErrMessagesCursors
+	}
+
+	rmr.Limit = defaultMessagesLimit
+	if values.Has("limit") {
+		limit, err := strconv.Atoi(values.Get("limit"))
+		if err != nil || limit <= 0 || limit > maxMessagesLimit {
+			return ErrMessagesLimit
+		}
+		rmr.Limit = limit
+	}
+
+	return nil
+}
+
 type ReadMessagesResponse struct {
 	Response
 	Messages []*models.Message `json:"messages"`
+	HasMore  bool              `json:"has_more"` // есть ли ещё сообщения в направлении листания
+}
+
+type MessageResponse struct {
+	Response
+	Message *models.Message `json:"message"`
 }
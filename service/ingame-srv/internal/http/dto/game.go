@@ -13,3 +13,15 @@ type GetParticipantsResponse struct {
 	Response
 	Participants json.RawMessage `json:"participants"`
 }
+
+type HintResponse struct {
+	Response
+	Row       int `json:"row"`
+	Col       int `json:"col"`
+	HintsLeft int `json:"hints_left"`
+}
+
+// EmoteRequest быстрая эмоция участника из фиксированного набора
+type EmoteRequest struct {
+	Emote string `json:"emote" validate:"required,oneof=gg wp thanks oops wow think laugh angry"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxOnlineIDs сколько пользователей можно проверить одним запросом
+const maxOnlineIDs = 200
+
+var ErrOnlineIDs = errors.New("ids должен быть списком id пользователей через запятую, не больше 200")
+
+type OnlineRequest struct {
+	IDs []int64
+}
+
+type OnlineResponse struct {
+	Response
+	Online []int64 `json:"online"`
+}
+
+// Render разбирает список id из query параметра ids
+func (r *OnlineRequest) Render(values url.Values) error {
+	raw := values.Get("ids")
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	if len(parts) > maxOnlineIDs {
+		return ErrOnlineIDs
+	}
+	r.IDs = make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return ErrOnlineIDs
+		}
+		r.IDs = append(r.IDs, id)
+	}
+	return nil
+}
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"ms4me/game_socket/internal/http/dto"
 	"ms4me/game_socket/internal/http/middlewares"
 	"ms4me/game_socket/internal/models"
+	storage "ms4me/game_socket/internal/redis"
+	"ms4me/game_socket/internal/service/chat"
+	"ms4me/game_socket/pkg/lib/validator"
 	"net/http"
 	"time"
 
@@ -37,6 +42,11 @@ func (h *Handlers) CreateMessage() http.HandlerFunc {
 			render.JSON(w, r, dto.ErrBody)
 			return
 		}
+		if err := validator.Validate(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(validator.GetDetailedError(err).Error()))
+			return
+		}
 
 		exists, err := h.redis.RoomExists(ctx, id)
 		if err != nil {
@@ -51,11 +61,17 @@ func (h *Handlers) CreateMessage() http.HandlerFunc {
 			return
 		}
 
+		text, err := h.moderator.Check(ctx, id, user.ID, req.Text)
+		if err != nil {
+			writeChatError(w, r, log, err)
+			return
+		}
+
 		message := &models.Message{
 			ID:              uuid.NewString(),
 			CreatorID:       user.ID,
 			CreatorUsername: user.Username,
-			Text:            req.Text,
+			Text:            text,
 			CreatedAt:       time.Now().UTC(),
 		}
 		messageBytes, err := json.Marshal(message)
@@ -73,6 +89,10 @@ func (h *Handlers) CreateMessage() http.HandlerFunc {
 			render.JSON(w, r, dto.ErrInternalError)
 			return
 		}
+		// сообщение уже сохранено, без отпечатка пропустится только проверка повтора
+		if err := h.moderator.Remember(ctx, id, user.ID, req.Text); err != nil {
+			log.Error("error remembering message", prettylogger.Err(err))
+		}
 
 		err = h.redis.PublishEvent(ctx, models.Event{
 			Type:     models.TypeNewMessage,
@@ -95,7 +115,7 @@ func (h *Handlers) CreateMessage() http.HandlerFunc {
 
 func (h *Handlers) GetMessages() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		const op = "handlers.CreateMessage"
+		const op = "handlers.GetMessages"
 
 		ctx := r.Context()
 		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
@@ -109,17 +129,191 @@ func (h *Handlers) GetMessages() http.HandlerFunc {
 		}
 		log := h.log.With(slog.String("op", op), slog.String("game_id", id), slog.Int64("user_id", user.ID))
 
-		messages, err := h.redis.ReadMessages(ctx, id)
+		var req dto.ReadMessagesRequest
+		if err := req.Render(r.URL.Query()); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(err.Error()))
+			return
+		}
+
+		messages, err := h.readMessages(ctx, id)
 		if err != nil {
 			log.Error("error read messages", prettylogger.Err(err))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		page, hasMore, err := chat.Page(messages, req.Before, req.After, req.Limit)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(err.Error()))
+			return
+		}
 
 		log.Info("messages read successfully")
 		render.JSON(w, r, dto.ReadMessagesResponse{
 			Response: dto.OK(),
-			Messages: messages,
+			Messages: page,
+			HasMore:  hasMore,
 		})
 	}
 }
+
+// EditMessage меняет текст сообщения. Автор может сделать это в течение chat.EditWindow после отправки
+func (h *Handlers) EditMessage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.EditMessage"
+
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+		w.Header().Set("Content-Type", "application/json")
+
+		id, messageID := chi.URLParamFromCtx(ctx, "id"), chi.URLParamFromCtx(ctx, "messageID")
+		if id == "" || messageID == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrIDIsEmpty)
+			return
+		}
+		log := h.log.With(slog.String("op", op), slog.String("game_id", id), slog.String("message_id", messageID), slog.Int64("user_id", user.ID))
+
+		var req dto.EditMessageRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrBody)
+			return
+		}
+		if err := validator.Validate(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(validator.GetDetailedError(err).Error()))
+			return
+		}
+
+		text, err := h.moderator.CheckEdit(ctx, id, user.ID, req.Text)
+		if err != nil {
+			writeChatError(w, r, log, err)
+			return
+		}
+
+		message, err := h.modifyMessage(ctx, id, messageID, user.ID, models.TypeEditMessage, func(message *models.Message) {
+			editedAt := time.Now().UTC()
+			message.Text = text
+			message.EditedAt = &editedAt
+		})
+		if err != nil {
+			writeChatError(w, r, log, err)
+			return
+		}
+
+		log.Info("message edited successfully")
+		render.JSON(w, r, dto.MessageResponse{
+			Response: dto.OK(),
+			Message:  message,
+		})
+	}
+}
+
+// DeleteMessage удаляет сообщение. Автор может сделать это в течение chat.EditWindow после отправки
+func (h *Handlers) DeleteMessage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.DeleteMessage"
+
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+		w.Header().Set("Content-Type", "application/json")
+
+		id, messageID := chi.URLParamFromCtx(ctx, "id"), chi.URLParamFromCtx(ctx, "messageID")
+		if id == "" || messageID == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrIDIsEmpty)
+			return
+		}
+		log := h.log.With(slog.String("op", op), slog.String("game_id", id), slog.String("message_id", messageID), slog.Int64("user_id", user.ID))
+
+		_, err := h.modifyMessage(ctx, id, messageID, user.ID, models.TypeDeleteMessage, func(message *models.Message) {
+			message.Text = ""
+			message.Deleted = true
+		})
+		if err != nil {
+			writeChatError(w, r, log, err)
+			return
+		}
+
+		log.Info("message deleted successfully")
+		render.JSON(w, r, dto.OK())
+	}
+}
+
+// modifyMessage применяет apply к сообщению автора userID, сохраняет его и рассылает участникам событие eventType
+func (h *Handlers) modifyMessage(ctx context.Context, gameID, messageID string, userID int64, eventType models.EventType, apply func(message *models.Message)) (*models.Message, error) {
+	message, err := h.redis.GetMessage(ctx, gameID, messageID)
+	if errors.Is(err, storage.ErrNil) {
+		return nil, chat.ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := chat.CanModify(message, userID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	apply(message)
+	err = h.redis.UpdateMessage(ctx, gameID, message)
+	if errors.Is(err, storage.ErrNil) {
+		return nil, chat.ErrMessageNotFound // сообщение удалили, пока мы его меняли
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	err = h.redis.PublishEvent(ctx, models.Event{
+		Type:     eventType,
+		UserID:   userID,
+		GameID:   gameID,
+		IsPublic: false,
+		Payload:  messageBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func writeChatError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, chat.ErrMessageNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, chat.ErrNotMessageAuthor), errors.Is(err, chat.ErrEditWindowExpired), errors.Is(err, chat.ErrMuted):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, chat.ErrTooManyMessages), errors.Is(err, chat.ErrDuplicateMessage):
+		w.WriteHeader(http.StatusTooManyRequests)
+	default:
+		log.Error("error handling chat message", prettylogger.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, dto.ErrInternalError)
+		return
+	}
+	render.JSON(w, r, dto.Error(err.Error()))
+}
+
+// readMessages читает чат идущей игры из redis, а историю закончившейся игры - из game-srv.
+// Если чат идущей игры успел истечь в redis, его сообщения берутся из последней архивации
+func (h *Handlers) readMessages(ctx context.Context, gameID string) ([]*models.Message, error) {
+	live, err := h.redis.RoomExists(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if live {
+		exists, err := h.redis.ChatExists(ctx, gameID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return h.redis.ReadMessages(ctx, gameID)
+		}
+	}
+	return h.gameClient.GetChat(gameID)
+}
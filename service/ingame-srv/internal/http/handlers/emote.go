@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"ms4me/game_socket/internal/http/dto"
+	"ms4me/game_socket/internal/http/middlewares"
+	"ms4me/game_socket/internal/models"
+	"ms4me/game_socket/pkg/lib/validator"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/jacute/prettylogger"
+)
+
+// Emote рассылает участникам комнаты быструю эмоцию. Эмоции не сохраняются в истории чата
+func (h *Handlers) Emote() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.Emote"
+
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+		w.Header().Set("Content-Type", "application/json")
+
+		id := chi.URLParamFromCtx(ctx, "id")
+		log := h.log.With(slog.String("op", op), slog.String("game_id", id), slog.Int64("user_id", user.ID))
+
+		var req dto.EmoteRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrBody)
+			return
+		}
+		if err := validator.Validate(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(validator.GetDetailedError(err).Error()))
+			return
+		}
+
+		if err := h.moderator.CheckEmote(ctx, id, user.ID); err != nil {
+			writeChatError(w, r, log, err)
+			return
+		}
+
+		payload, err := json.Marshal(&models.EmoteEvent{
+			UserID:   user.ID,
+			Username: user.Username,
+			Emote:    req.Emote,
+		})
+		if err != nil {
+			log.Error("error marshalling emote", prettylogger.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternalError)
+			return
+		}
+		err = h.redis.PublishEvent(ctx, models.Event{
+			Type:     models.TypeEmote,
+			UserID:   user.ID,
+			GameID:   id,
+			IsPublic: false,
+			Payload:  payload,
+		})
+		if err != nil {
+			log.Error("error publishing event", prettylogger.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternalError)
+			return
+		}
+
+		render.JSON(w, r, dto.OK())
+	}
+}
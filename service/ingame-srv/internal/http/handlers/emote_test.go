@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"ms4me/game_socket/internal/http/dto"
+	"ms4me/game_socket/internal/service/chat"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// mutedStorage заглушает всех, поэтому прошедшая проверку набора эмоция останавливается на модераторе
+type mutedStorage struct{}
+
+func (mutedStorage) IsMuted(context.Context, string, int64) (bool, error) {
+	return true, nil
+}
+
+func (mutedStorage) TakeChatToken(context.Context, string, int64, int, time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (mutedStorage) GetLastMessage(context.Context, string, int64) (string, error) {
+	return "", nil
+}
+
+func (mutedStorage) SetLastMessage(context.Context, string, int64, string, time.Duration) error {
+	return nil
+}
+
+func TestEmoteAllowList(t *testing.T) {
+	h := &Handlers{
+		log:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		moderator: chat.NewModerator(mutedStorage{}, chat.NewBannedWords(nil)),
+	}
+
+	testCases := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "allowed", body: `{"emote":"gg"}`, status: http.StatusForbidden},
+		{name: "allowed last", body: `{"emote":"angry"}`, status: http.StatusForbidden},
+		{name: "unknown", body: `{"emote":"lol"}`, status: http.StatusBadRequest},
+		{name: "case matters", body: `{"emote":"GG"}`, status: http.StatusBadRequest},
+		{name: "free text", body: `{"emote":"gg wp"}`, status: http.StatusBadRequest},
+		{name: "empty", body: `{"emote":""}`, status: http.StatusBadRequest},
+		{name: "broken json", body: `{"emote":`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := userRequest(http.MethodPost, "/games/room/emote", tc.body)
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", "room")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+
+			w := httptest.NewRecorder()
+			h.Emote()(w, r)
+
+			require.Equal(t, tc.status, w.Code)
+			var resp dto.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Equal(t, dto.StatusError, resp.Status)
+			if tc.status == http.StatusForbidden {
+				require.Equal(t, chat.ErrMuted.Error(), resp.Error)
+			}
+		})
+	}
+}
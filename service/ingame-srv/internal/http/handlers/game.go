@@ -1,26 +1,21 @@
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
 	"log/slog"
 	"ms4me/game_socket/internal/http/dto"
 	"ms4me/game_socket/internal/http/middlewares"
-	"ms4me/game_socket/internal/models"
+	storage "ms4me/game_socket/internal/redis"
 	"ms4me/game_socket/internal/service/game"
+	"ms4me/game_socket/internal/service/play"
 	"ms4me/game_socket/pkg/lib/validator"
 	"net/http"
-	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/jacute/prettylogger"
 )
 
-var (
-	ErrNotYourGame = dto.Error("Пользователь отсутсвует среди участников игры")
-)
-
 func (h *Handlers) GetGameInfo() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.GetParticipants"
@@ -58,14 +53,18 @@ func (h *Handlers) GetGameInfo() http.HandlerFunc {
 			return
 		}
 
-		if _, ok := roomParticipantsMap[strconv.Itoa(int(user.ID))]; !ok {
-			log.Info("user not in game")
-			w.WriteHeader(http.StatusForbidden)
-			render.JSON(w, r, ErrNotYourGame)
+		// Общее поле есть только в совместной игре, тогда оно показывается как поле каждого участника
+		board, err := h.redis.GetBoard(ctx, id)
+		if err == nil {
+			roomParticipantsMap = play.WithBoard(roomParticipantsMap, board)
+		} else if !errors.Is(err, storage.ErrNil) {
+			log.Error("error getting shared board", prettylogger.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternalError)
 			return
 		}
 
-		data, err := marshalGameData(roomParticipantsMap)
+		data, err := play.MarshalGameData(roomParticipantsMap)
 		if err != nil {
 			log.Error("error marshalling room participants", prettylogger.Err(err))
 			w.WriteHeader(http.StatusInternalServerError)
@@ -104,183 +103,53 @@ func (h *Handlers) OpenCell() http.HandlerFunc {
 		id := chi.URLParamFromCtx(ctx, "id")
 		log = log.With(slog.String("game_id", id))
 
-		exists, err := h.redis.RoomExists(ctx, id)
+		err := h.play.OpenCell(ctx, id, user.ID, req.Row, req.Col)
 		if err != nil {
-			log.Error("error getting room exist", prettylogger.Err(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, dto.ErrInternalError)
-			return
-		}
-		if !exists {
-			w.WriteHeader(http.StatusNotFound)
-			render.JSON(w, r, dto.Error("Игра не найдена"))
+			renderPlayError(w, r, log, err)
 			return
 		}
 
-		participants, err := h.redis.GetClientsInChannel(ctx, id)
-		if err != nil {
-			log.Error("error getting room participants", prettylogger.Err(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, dto.ErrInternalError)
-			return
-		}
+		render.JSON(w, r, dto.OK())
+	}
+}
 
-		var loseEvent *models.LoseEvent
-		var winEvent *models.WinEvent
-		var userParticipant *models.RoomParticipant
-		// Ищем пользователя среди участников
-		for _, participant := range participants {
-			if participant.ID == user.ID {
-				userParticipant = participant
-				break
-			}
-		}
+func (h *Handlers) Flag() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.Flag"
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+		log := h.log.With(slog.String("op", op), slog.Int64("user_id", user.ID))
 
-		if userParticipant == nil {
-			log.Info("user not in game")
-			w.WriteHeader(http.StatusForbidden)
-			render.JSON(w, r, ErrNotYourGame)
+		var req dto.ClickCellRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrBody)
 			return
 		}
 
-		// Если у игрока поля нет, то генерируем
-		if userParticipant.Field == nil {
-			userParticipant.Field = game.CreateField(req.Row, req.Col)
-		}
-		err = userParticipant.Field.OpenCell(req.Row, req.Col)
-		if err != nil {
-			if errors.Is(err, game.ErrAlreadyOpen) {
-				log.Debug("cell already open")
-				w.WriteHeader(http.StatusBadRequest)
-				render.JSON(w, r, dto.Error(game.ErrAlreadyOpen.Error()))
-				return
-			}
-			if errors.Is(err, game.ErrFieldSize) {
-				log.Debug("open outside the field")
-				render.JSON(w, r, dto.Error(game.ErrFieldSize.Error()))
-				return
-			}
-			log.Error("error opening cell", prettylogger.Err(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, dto.ErrInternalError)
+		if err := validator.Validate(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(validator.GetDetailedError(err).Error()))
 			return
 		}
-		if userParticipant.Field.MineIsOpen {
-			log.Info("user lose", slog.Int64("loser_id", userParticipant.ID))
-			loseEvent = &models.LoseEvent{
-				LoserID:       userParticipant.ID,
-				LoserUsername: userParticipant.Username,
-			}
-		} else if userParticipant.Field.IsWin() {
-			log.Info("user lose", slog.Int64("winner_id", userParticipant.ID))
-			winEvent = &models.WinEvent{
-				WinnerID:       userParticipant.ID,
-				WinnerUsername: userParticipant.Username,
-			}
-		}
 
-		err = h.redis.AddClientToChannel(ctx, id, userParticipant.ID, userParticipant)
-		if err != nil {
-			log.Error("error saving participant info", prettylogger.Err(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, dto.ErrInternalError)
-			return
-		}
+		id := chi.URLParamFromCtx(ctx, "id")
+		log = log.With(slog.String("game_id", id))
 
-		payload, err := marshalGameData(participants)
-		if err != nil {
-			log.Error("error marshalling field", prettylogger.Err(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, dto.ErrInternalError)
-			return
-		}
-		err = h.redis.PublishEvent(ctx, models.Event{
-			Type:     models.TypeClickGame,
-			UserID:   user.ID,
-			GameID:   id,
-			IsPublic: false,
-			Payload:  payload,
-		})
+		err := h.play.Flag(ctx, id, user.ID, req.Row, req.Col)
 		if err != nil {
-			log.Error("error publishing event", prettylogger.Err(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, dto.ErrInternalError)
+			renderPlayError(w, r, log, err)
 			return
 		}
 
-		if loseEvent != nil {
-			resultMarshalled, err := json.Marshal(&loseEvent)
-			if err != nil {
-				log.Error("error marshalling result", prettylogger.Err(err))
-				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, dto.ErrInternalError)
-				return
-			}
-			winner := getParticipantWithoutOpenMine(participants)
-			if winner == nil {
-				log.Error("no winner in room")
-				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, dto.ErrInternalError)
-				return
-			}
-			err = h.gameClient.Close(id, winner.ID)
-			if err != nil {
-				log.Error("error closing game", prettylogger.Err(err))
-				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, dto.ErrInternalError)
-				return
-			}
-			err = h.redis.PublishEvent(ctx, models.Event{
-				Type:     models.TypeLoseGame,
-				UserID:   user.ID,
-				GameID:   id,
-				IsPublic: false,
-				Payload:  resultMarshalled,
-			})
-			if err != nil {
-				log.Error("error publishing event", prettylogger.Err(err))
-				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, dto.ErrInternalError)
-				return
-			}
-		}
-		if winEvent != nil {
-			resultMarshalled, err := json.Marshal(&winEvent)
-			if err != nil {
-				log.Error("error marshalling result", prettylogger.Err(err))
-				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, dto.ErrInternalError)
-				return
-			}
-			err = h.gameClient.Close(id, winEvent.WinnerID)
-			if err != nil {
-				log.Error("error closing game", prettylogger.Err(err))
-				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, dto.ErrInternalError)
-				return
-			}
-			err = h.redis.PublishEvent(ctx, models.Event{
-				Type:     models.TypeWinGame,
-				UserID:   user.ID,
-				GameID:   id,
-				IsPublic: false,
-				Payload:  resultMarshalled,
-			})
-			if err != nil {
-				log.Error("error publishing event", prettylogger.Err(err))
-				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, dto.ErrInternalError)
-				return
-			}
-		}
-
 		render.JSON(w, r, dto.OK())
 	}
 }
 
-func (h *Handlers) Flag() http.HandlerFunc {
+// Chord открывает клетки вокруг открытой клетки, если вокруг неё стоит столько же флагов, сколько мин
+func (h *Handlers) Chord() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		const op = "handlers.Flag"
+		const op = "handlers.Chord"
 		ctx := r.Context()
 		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
 		log := h.log.With(slog.String("op", op), slog.Int64("user_id", user.ID))
@@ -300,95 +169,46 @@ func (h *Handlers) Flag() http.HandlerFunc {
 
 		id := chi.URLParamFromCtx(ctx, "id")
 		log = log.With(slog.String("game_id", id))
-		participants, err := h.redis.GetClientsInChannel(ctx, id)
-		if err != nil {
-			log.Error("error getting room participants", prettylogger.Err(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, dto.ErrInternalError)
-			return
-		}
 
-		userParticipant, ok := participants[strconv.Itoa(int(user.ID))]
-		if ok {
-			err := userParticipant.Field.SetFlag(req.Row, req.Col)
-			if err != nil {
-				if errors.Is(err, game.ErrFlagOnOpenCell) {
-					log.Debug("error setting flag on open cell")
-					w.WriteHeader(http.StatusBadRequest)
-					render.JSON(w, r, dto.Error(game.ErrFlagOnOpenCell.Error()))
-					return
-				}
-				if errors.Is(err, game.ErrFieldSize) {
-					log.Debug("open outside the field")
-					render.JSON(w, r, dto.Error(game.ErrFieldSize.Error()))
-					return
-				}
-				log.Error("error setting flag", prettylogger.Err(err))
-				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, dto.ErrInternalError)
-				return
-			}
-			err = h.redis.AddClientToChannel(ctx, id, userParticipant.ID, userParticipant)
-			if err != nil {
-				log.Error("error saving participant info", prettylogger.Err(err))
-			}
-		} else {
-			log.Info("user not in game")
-			w.WriteHeader(http.StatusForbidden)
-			render.JSON(w, r, ErrNotYourGame)
-			return
-		}
-
-		payload, err := marshalGameData(participants)
+		err := h.play.Chord(ctx, id, user.ID, req.Row, req.Col)
 		if err != nil {
-			log.Error("error marshalling field", prettylogger.Err(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, dto.ErrInternalError)
+			renderPlayError(w, r, log, err)
 			return
 		}
-		err = h.redis.PublishEvent(ctx, models.Event{
-			Type:     models.TypeClickGame,
-			UserID:   user.ID,
-			GameID:   id,
-			IsPublic: false,
-			Payload:  payload,
-		})
-		if err != nil {
-			h.log.Error("error publishing event", prettylogger.Err(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, dto.ErrInternalError)
-			return
-		}
-		render.JSON(w, r, dto.OK())
-	}
-}
 
-// marshalGameData подготаливает json с данными по игре для отправки клиенту, маскируя поля json, которые не должны передаваться (расположения мин)
-func marshalGameData(participants map[string]*models.RoomParticipant) ([]byte, error) {
-	arrParticipants := make([]*models.RoomParticipant, 0)
-	for _, participant := range participants {
-		if participant.Field != nil {
-			for _, row := range participant.Field.Grid {
-				for _, c := range row {
-					c.NeighborMines = 0
-					c.HasMine = nil
-				}
-			}
-		}
-		arrParticipants = append(arrParticipants, participant)
-	}
-	data, err := json.Marshal(arrParticipants)
-	if err != nil {
-		return nil, err
+		render.JSON(w, r, dto.OK())
 	}
-	return data, nil
 }
 
-func getParticipantWithoutOpenMine(participants map[string]*models.RoomParticipant) *models.RoomParticipant {
-	for _, rp := range participants {
-		if rp.Field == nil || (rp.Field != nil && !rp.Field.MineIsOpen) {
-			return rp
-		}
+// renderPlayError отдаёт клиенту ошибку хода с подходящим статусом
+func renderPlayError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, play.ErrRoomNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, play.ErrNotParticipant):
+		log.Info("user not in game")
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, play.ErrHintPenalty):
+		w.WriteHeader(http.StatusTooManyRequests)
+	case errors.Is(err, play.ErrBoardBusy):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, play.ErrFieldNotCreated),
+		errors.Is(err, play.ErrNoHintsLeft),
+		errors.Is(err, play.ErrNoSafeCells),
+		errors.Is(err, play.ErrBoardFinished),
+		errors.Is(err, game.ErrAlreadyOpen),
+		errors.Is(err, game.ErrFlagOnOpenCell),
+		errors.Is(err, game.ErrChordClosed),
+		errors.Is(err, game.ErrChordFlags):
+		log.Debug("invalid move", prettylogger.Err(err))
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, game.ErrFieldSize):
+		log.Debug("move outside the field")
+	default:
+		log.Error("error making move", prettylogger.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, dto.ErrInternalError)
+		return
 	}
-	return nil
+	render.JSON(w, r, dto.Error(err.Error()))
 }
@@ -3,6 +3,8 @@ package handlers
 import (
 	"log/slog"
 	storage "ms4me/game_socket/internal/redis"
+	"ms4me/game_socket/internal/service/chat"
+	"ms4me/game_socket/internal/service/play"
 	ws "ms4me/game_socket/internal/ws/server"
 	gameclient "ms4me/game_socket/pkg/game_client"
 )
@@ -12,6 +14,8 @@ type Handlers struct {
 	redis      *storage.Redis
 	wsSrv      *ws.Server
 	gameClient *gameclient.GameClient
+	play       *play.Play
+	moderator  *chat.Moderator
 }
 
 func New(
@@ -19,11 +23,15 @@ func New(
 	redis *storage.Redis,
 	wsSrv *ws.Server,
 	gc *gameclient.GameClient,
+	play *play.Play,
+	moderator *chat.Moderator,
 ) *Handlers {
 	return &Handlers{
 		log:        log,
 		redis:      redis,
 		wsSrv:      wsSrv,
 		gameClient: gc,
+		play:       play,
+		moderator:  moderator,
 	}
 }
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"log/slog"
+	"ms4me/game_socket/internal/http/dto"
+	"ms4me/game_socket/internal/http/middlewares"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// Hint открывает участнику одну безопасную клетку, расходуя подсказку из бюджета игры и накладывая штраф по времени
+func (h *Handlers) Hint() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.Hint"
+
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+		w.Header().Set("Content-Type", "application/json")
+
+		id := chi.URLParamFromCtx(ctx, "id")
+		log := h.log.With(slog.String("op", op), slog.String("game_id", id), slog.Int64("user_id", user.ID))
+
+		hint, hintsLeft, err := h.play.Hint(ctx, id, user.ID)
+		if err != nil {
+			renderPlayError(w, r, log, err)
+			return
+		}
+
+		render.JSON(w, r, dto.HintResponse{
+			Response:  dto.OK(),
+			Row:       hint.Row,
+			Col:       hint.Col,
+			HintsLeft: hintsLeft,
+		})
+	}
+}
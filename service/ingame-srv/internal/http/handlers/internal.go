@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"ms4me/game_socket/internal/http/dto"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
 	"github.com/jacute/prettylogger"
 )
 
@@ -19,6 +21,10 @@ func (h *Handlers) Ready() http.HandlerFunc {
 		}
 
 		for _, participant := range participants {
+			// Боты играют без websocket и всегда готовы
+			if participant.Bot != "" {
+				continue
+			}
 			result := h.wsSrv.CheckRoomConn(participant.ID, id)
 			if !result {
 				w.WriteHeader(http.StatusTooEarly) // игроки не готовы к началу игры
@@ -28,3 +34,20 @@ func (h *Handlers) Ready() http.HandlerFunc {
 		w.WriteHeader(http.StatusOK)
 	}
 }
+
+// Online отдаёт, кто из пользователей с id из query сейчас подключён по вебсокету
+func (h *Handlers) Online() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.OnlineRequest
+		if err := req.Render(r.URL.Query()); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(err.Error()))
+			return
+		}
+
+		render.JSON(w, r, dto.OnlineResponse{
+			Response: dto.OK(),
+			Online:   h.wsSrv.Online(req.IDs),
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"ms4me/game_socket/internal/http/dto"
+	"ms4me/game_socket/internal/http/middlewares"
+	"ms4me/game_socket/internal/models"
+	"ms4me/game_socket/internal/service/chat"
+	"ms4me/game_socket/pkg/lib/validator"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/jacute/prettylogger"
+)
+
+// CreateLobbyMessage отправляет сообщение в общий чат лобби всем, кто сейчас в лобби
+func (h *Handlers) CreateLobbyMessage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.CreateLobbyMessage"
+
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+		w.Header().Set("Content-Type", "application/json")
+		log := h.log.With(slog.String("op", op), slog.Int64("user_id", user.ID))
+
+		var req dto.CreateMessageRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrBody)
+			return
+		}
+		if err := validator.Validate(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(validator.GetDetailedError(err).Error()))
+			return
+		}
+
+		text, err := h.moderator.Check(ctx, chat.LobbyChannel, user.ID, req.Text)
+		if err != nil {
+			writeChatError(w, r, log, err)
+			return
+		}
+
+		message := &models.Message{
+			ID:              uuid.NewString(),
+			CreatorID:       user.ID,
+			CreatorUsername: user.Username,
+			Text:            text,
+			CreatedAt:       time.Now().UTC(),
+		}
+		messageBytes, err := json.Marshal(message)
+		if err != nil {
+			log.Error("error marshalling message", prettylogger.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternalError)
+			return
+		}
+
+		err = h.redis.CreateLobbyMessage(ctx, messageBytes)
+		if err != nil {
+			log.Error("error creating lobby message", prettylogger.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternalError)
+			return
+		}
+		// сообщение уже сохранено, без отпечатка пропустится только проверка повтора
+		if err := h.moderator.Remember(ctx, chat.LobbyChannel, user.ID, req.Text); err != nil {
+			log.Error("error remembering message", prettylogger.Err(err))
+		}
+
+		err = h.redis.PublishEvent(ctx, models.Event{
+			Type:     models.TypeLobbyMessage,
+			UserID:   user.ID,
+			IsPublic: true,
+			Payload:  messageBytes,
+		})
+		if err != nil {
+			log.Error("error publishing event", prettylogger.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternalError)
+			return
+		}
+
+		log.Info("lobby message created successfully")
+		render.JSON(w, r, dto.OK())
+	}
+}
+
+// GetLobbyMessages отдаёт страницу истории общего чата лобби
+func (h *Handlers) GetLobbyMessages() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.GetLobbyMessages"
+
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+		w.Header().Set("Content-Type", "application/json")
+		log := h.log.With(slog.String("op", op), slog.Int64("user_id", user.ID))
+
+		var req dto.ReadMessagesRequest
+		if err := req.Render(r.URL.Query()); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(err.Error()))
+			return
+		}
+
+		messages, err := h.redis.ReadLobbyMessages(ctx, req.Before, req.After, req.Limit)
+		if err != nil {
+			log.Error("error read lobby messages", prettylogger.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternalError)
+			return
+		}
+		page, hasMore, err := chat.Page(messages, req.Before, req.After, req.Limit)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(err.Error()))
+			return
+		}
+
+		log.Info("lobby messages read successfully")
+		render.JSON(w, r, dto.ReadMessagesResponse{
+			Response: dto.OK(),
+			Messages: page,
+			HasMore:  hasMore,
+		})
+	}
+}
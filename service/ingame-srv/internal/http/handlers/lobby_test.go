@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"ms4me/game_socket/internal/http/dto"
+	"ms4me/game_socket/internal/http/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// userRequest собирает запрос авторизованного пользователя. Проверки запроса срабатывают до обращения к redis,
+// поэтому хендлерам достаточно пустых зависимостей
+func userRequest(method, target, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	user := &middlewares.User{ID: 1, Username: "user"}
+	return r.WithContext(context.WithValue(r.Context(), middlewares.UserContextKey, user))
+}
+
+func TestCreateLobbyMessageInvalid(t *testing.T) {
+	h := &Handlers{log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	testCases := []struct {
+		name string
+		body string
+		want dto.Response
+	}{
+		{name: "broken json", body: "{", want: dto.ErrBody},
+		{name: "empty text", body: `{"text":""}`},
+		{name: "too long text", body: `{"text":"` + strings.Repeat("a", 257) + `"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.CreateLobbyMessage()(w, userRequest(http.MethodPost, "/lobby/messages", tc.body))
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			var resp dto.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.NotEmpty(t, resp.Error)
+			if tc.want.Error != "" {
+				require.Equal(t, tc.want, resp)
+			}
+		})
+	}
+}
+
+func TestGetLobbyMessagesInvalid(t *testing.T) {
+	h := &Handlers{log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	testCases := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{name: "both cursors", query: "?before=a&after=b", err: dto.ErrMessagesCursors},
+		{name: "limit not a number", query: "?limit=ten", err: dto.ErrMessagesLimit},
+		{name: "zero limit", query: "?limit=0", err: dto.ErrMessagesLimit},
+		{name: "limit too big", query: "?limit=101", err: dto.ErrMessagesLimit},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.GetLobbyMessages()(w, userRequest(http.MethodGet, "/lobby/messages"+tc.query, ""))
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			var resp dto.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Equal(t, dto.Error(tc.err.Error()), resp)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"ms4me/game_socket/internal/http/dto"
+	"ms4me/game_socket/internal/http/middlewares"
+	storage "ms4me/game_socket/internal/redis"
+	"ms4me/game_socket/pkg/lib/validator"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/jacute/prettylogger"
+)
+
+var (
+	ErrOnlyOwnerCanMute = dto.Error("Заглушать участников может только владелец игры")
+	ErrMuteSelf         = dto.Error("Нельзя заглушить самого себя")
+	ErrMuteTarget       = dto.Error("Участник не найден в игре")
+)
+
+// Mute запрещает участнику писать в чат игры до её окончания. Доступно только владельцу игры
+func (h *Handlers) Mute() http.HandlerFunc {
+	return h.changeMute("handlers.Mute", h.redis.MuteUser)
+}
+
+// Unmute снимает запрет писать в чат игры
+func (h *Handlers) Unmute() http.HandlerFunc {
+	return h.changeMute("handlers.Unmute", h.redis.UnmuteUser)
+}
+
+func (h *Handlers) changeMute(op string, apply func(ctx context.Context, roomID string, userID int64) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		user := ctx.Value(middlewares.UserContextKey).(*middlewares.User)
+		w.Header().Set("Content-Type", "application/json")
+
+		id := chi.URLParamFromCtx(ctx, "id")
+		log := h.log.With(slog.String("op", op), slog.String("game_id", id), slog.Int64("user_id", user.ID))
+
+		var req dto.MuteRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrBody)
+			return
+		}
+		if err := validator.Validate(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(validator.GetDetailedError(err).Error()))
+			return
+		}
+		if req.UserID == user.ID {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, ErrMuteSelf)
+			return
+		}
+
+		owner, err := h.redis.GetClientInChannel(ctx, id, user.ID)
+		if errors.Is(err, storage.ErrNil) {
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, middlewares.ErrGameNotFound) // участники проверены, значит комната уже удалена
+			return
+		}
+		if err != nil {
+			log.Error("error getting room participant", prettylogger.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternalError)
+			return
+		}
+		if !owner.IsOwner {
+			w.WriteHeader(http.StatusForbidden)
+			render.JSON(w, r, ErrOnlyOwnerCanMute)
+			return
+		}
+
+		inRoom, err := h.redis.IsClientInChannel(ctx, id, req.UserID)
+		if err != nil {
+			log.Error("error checking room participant", prettylogger.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternalError)
+			return
+		}
+		if !inRoom {
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, ErrMuteTarget)
+			return
+		}
+
+		err = apply(ctx, id, req.UserID)
+		if err != nil {
+			log.Error("error changing chat mute", prettylogger.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternalError)
+			return
+		}
+
+		log.Info("chat mute changed successfully", slog.Int64("target_id", req.UserID))
+		render.JSON(w, r, dto.OK())
+	}
+}
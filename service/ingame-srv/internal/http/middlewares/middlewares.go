@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"log/slog"
+	storage "ms4me/game_socket/internal/redis"
 	gameclient "ms4me/game_socket/pkg/game_client"
 )
 
@@ -9,12 +10,14 @@ type Middlewares struct {
 	log        *slog.Logger
 	jwtSecret  []byte
 	gameClient *gameclient.GameClient
+	redis      *storage.Redis
 }
 
-func New(log *slog.Logger, jwtSecret []byte, gameClient *gameclient.GameClient) *Middlewares {
+func New(log *slog.Logger, jwtSecret []byte, gameClient *gameclient.GameClient, redis *storage.Redis) *Middlewares {
 	return &Middlewares{
 		log:        log,
 		jwtSecret:  jwtSecret,
 		gameClient: gameClient,
+		redis:      redis,
 	}
 }
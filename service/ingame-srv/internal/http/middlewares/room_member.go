@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"ms4me/game_socket/internal/http/dto"
+	gameclient "ms4me/game_socket/pkg/game_client"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/jacute/prettylogger"
+)
+
+var (
+	ErrNotYourGame  = dto.Error("Пользователь отсутсвует среди участников игры")
+	ErrGameNotFound = dto.Error("Игра не найдена")
+)
+
+// RoomMember пропускает к игре и её чату только участников. Пока комната существует, участники берутся из redis,
+// после её удаления - из списка игроков game-srv, чтобы история чата оставалась доступна тем, кто играл
+func (mw *Middlewares) RoomMember() func(next http.Handler) http.Handler {
+	const op = "middlewares.RoomMember"
+
+	return func(next http.Handler) http.Handler {
+		log := mw.log.With(
+			slog.String("op", op),
+		)
+		log.Info("room member middleware enabled")
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			user := ctx.Value(UserContextKey).(*User)
+
+			id := chi.URLParamFromCtx(ctx, "id")
+			if id == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, dto.ErrIDIsEmpty)
+				return
+			}
+
+			member, err := mw.isRoomMember(ctx, id, user.ID)
+			if errors.Is(err, gameclient.ErrGameNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, ErrGameNotFound)
+				return
+			}
+			if err != nil {
+				log.Error("error checking room membership", slog.String("game_id", id), prettylogger.Err(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, dto.ErrInternalError)
+				return
+			}
+			if !member {
+				log.Info("user not in game", slog.String("game_id", id), slog.Int64("user_id", user.ID))
+				w.WriteHeader(http.StatusForbidden)
+				render.JSON(w, r, ErrNotYourGame)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func (mw *Middlewares) isRoomMember(ctx context.Context, roomID string, userID int64) (bool, error) {
+	exists, err := mw.redis.RoomExists(ctx, roomID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return mw.redis.IsClientInChannel(ctx, roomID, userID)
+	}
+
+	players, err := mw.gameClient.GetPlayers(roomID)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(players, func(player *gameclient.Player) bool { return player.ID == userID }), nil
+}
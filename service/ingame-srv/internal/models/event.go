@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"ms4me/game_socket/internal/service/game"
+	"time"
 )
 
 type EventType int
@@ -20,6 +21,21 @@ const (
 	TypeWinGame
 
 	TypeNewMessage
+
+	TypeDiffGame
+	TypeCommitField
+
+	TypeTournamentRound
+
+	TypeEditMessage
+	TypeDeleteMessage
+
+	TypeLobbyMessage
+
+	TypeEmote
+
+	TypeDirectMessage
+	TypeInvite
 )
 
 type Event struct {
@@ -31,6 +47,39 @@ type Event struct {
 	Payload  json.RawMessage `json:"payload,omitempty"`
 }
 
+// JoinEvent необязательные данные события входа в игру
+type JoinEvent struct {
+	Bot string `json:"bot,omitempty"` // уровень бота, если в игру добавлен бот
+}
+
+// EmoteEvent быстрая эмоция участника, рассылается комнате и не сохраняется в чате
+type EmoteEvent struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Emote    string `json:"emote"`
+}
+
+// PersonalEvent событие для одного пользователя, например личное сообщение или приглашение в игру.
+// Остальные поля полезной нагрузки передаются клиенту как есть
+type PersonalEvent struct {
+	RecipientID int64 `json:"recipient_id"`
+}
+
+// TournamentPairing пара участников раунда турнира и созданная для них игра
+type TournamentPairing struct {
+	GameID  string  `json:"game_id"`
+	Players []int64 `json:"players"`
+}
+
+// TournamentRoundEvent начало раунда турнира, рассылается участникам в лобби
+type TournamentRoundEvent struct {
+	TournamentID string               `json:"tournament_id"`
+	Title        string               `json:"title"`
+	Round        int                  `json:"round"`
+	Deadline     time.Time            `json:"deadline"`
+	Pairings     []*TournamentPairing `json:"pairings"`
+}
+
 type CreateEvent struct {
 	ID        string `json:"id"`
 	OwnerID   int64  `json:"owner_id"`
@@ -44,19 +93,57 @@ type ClickEvent struct {
 	Field    *game.Field `json:"field"`
 }
 
+// FieldDiff изменения поля участника после хода
+type FieldDiff struct {
+	Changes    []*game.CellChange `json:"changes"`
+	CellsOpen  int                `json:"cells_open"`
+	MineIsOpen bool               `json:"mine_is_open"`
+	Flags      int                `json:"flags"`
+	Score      int                `json:"score"`
+	Lives      int                `json:"lives"`              // оставшиеся жизни, 0 в обычной игре или когда жизни кончились
+	Exploded   int                `json:"exploded"`           // количество взорванных мин
+	MovedBy    int64              `json:"moved_by,omitempty"` // кто сделал ход на общем поле в совместной игре
+}
+
+// CommitEvent commitment поля участника, публикуемая при его генерации
+type CommitEvent struct {
+	UserID     int64  `json:"user_id"`
+	Commitment string `json:"commitment"`
+}
+
 type LoseEvent struct {
-	LoserID       int64  `json:"loser_id"`
-	LoserUsername string `json:"loser_username"`
+	LoserID       int64                   `json:"loser_id"`
+	LoserUsername string                  `json:"loser_username"`
+	Reveals       map[string]*game.Reveal `json:"reveals,omitempty"` // раскрытие полей участников по id
+	Hints         map[string]int          `json:"hints,omitempty"`   // количество использованных подсказок по id
+	Scores        map[string]int          `json:"scores,omitempty"`  // счёт по id в игре на очки
+	Team          bool                    `json:"team,omitempty"`    // совместная игра, проиграли все участники
 }
 
 type WinEvent struct {
-	WinnerID       int64  `json:"winner_id"`
-	WinnerUsername string `json:"winner_username"`
+	WinnerID       int64                   `json:"winner_id"`
+	WinnerUsername string                  `json:"winner_username"`
+	Reveals        map[string]*game.Reveal `json:"reveals,omitempty"` // раскрытие полей участников по id
+	Hints          map[string]int          `json:"hints,omitempty"`   // количество использованных подсказок по id
+	Scores         map[string]int          `json:"scores,omitempty"`  // счёт по id в игре на очки
+	Team           bool                    `json:"team,omitempty"`    // совместная игра, победили все участники
 }
 
 type RoomParticipant struct {
-	ID       int64       `json:"id"`
-	Username string      `json:"username"`
-	IsOwner  bool        `json:"is_owner"`
-	Field    *game.Field `json:"field"`
+	ID           int64       `json:"id"`
+	Username     string      `json:"username"`
+	IsOwner      bool        `json:"is_owner"`
+	Moves        int         `json:"moves"`
+	HintsUsed    int         `json:"hints_used"`
+	PenaltyUntil *time.Time  `json:"penalty_until,omitempty"` // до этого момента ходы запрещены после подсказки
+	Bot          string      `json:"bot,omitempty"`           // уровень бота, пусто для людей
+	Score        int         `json:"score"`                   // счёт в игре на очки
+	StartedAt    *time.Time  `json:"started_at,omitempty"`    // время первого хода, от него считается бонус за скорость
+	CellsOpened  int         `json:"cells_opened,omitempty"`  // сколько клеток участник открыл на общем поле в совместной игре
+	Field        *game.Field `json:"field"`
+}
+
+// HasPenalty проверяет, действует ли ещё штраф за подсказку
+func (rp *RoomParticipant) HasPenalty() bool {
+	return rp.PenaltyUntil != nil && time.Now().Before(*rp.PenaltyUntil)
 }
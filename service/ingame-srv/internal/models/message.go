@@ -3,9 +3,11 @@ package models
 import "time"
 
 type Message struct {
-	ID              string    `json:"id"`
-	CreatorID       int64     `json:"creator_id"`
-	CreatorUsername string    `json:"creator_username"`
-	Text            string    `json:"text"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              string     `json:"id"`
+	CreatorID       int64      `json:"creator_id"`
+	CreatorUsername string     `json:"creator_username"`
+	Text            string     `json:"text"`
+	CreatedAt       time.Time  `json:"created_at"`
+	EditedAt        *time.Time `json:"edited_at,omitempty"` // время последнего изменения текста автором
+	Deleted         bool       `json:"deleted,omitempty"`   // удалённое сообщение остаётся в чате без текста, чтобы не сдвигать курсоры
 }
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ms4me/game_socket/internal/service/game"
+	"time"
+
+	redisdb "github.com/redis/go-redis/v9"
+)
+
+// boardLockTTL через сколько блокировка общего поля снимается сама, если ход завершился аварийно
+const boardLockTTL = 5 * time.Second
+
+// unlockBoardScript удаляет блокировку, только если она всё ещё принадлежит тому, кто её захватил
+var unlockBoardScript = redisdb.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// SaveBoard сохраняет общее поле комнаты в совместной игре
+func (rc *Redis) SaveBoard(ctx context.Context, roomID string, field *game.Field) error {
+	key := fmt.Sprintf("board:%s", roomID)
+	return rc.DB.Set(ctx, key, field.Encode(), 0).Err()
+}
+
+// GetBoard возвращает общее поле комнаты или ErrNil, если поле ещё не создано
+func (rc *Redis) GetBoard(ctx context.Context, roomID string) (*game.Field, error) {
+	key := fmt.Sprintf("board:%s", roomID)
+
+	data, err := rc.DB.Get(ctx, key).Bytes()
+	if errors.Is(err, redisdb.Nil) {
+		return nil, ErrNil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return game.DecodeField(data)
+}
+
+// LockBoard захватывает блокировку общего поля, чтобы ходы участников применялись по очереди.
+// Возвращает false, если поле уже заблокировано другим ходом
+func (rc *Redis) LockBoard(ctx context.Context, roomID string, token string) (bool, error) {
+	key := fmt.Sprintf("board-lock:%s", roomID)
+	return rc.DB.SetNX(ctx, key, token, boardLockTTL).Result()
+}
+
+// UnlockBoard снимает блокировку общего поля, захваченную с token
+func (rc *Redis) UnlockBoard(ctx context.Context, roomID string, token string) error {
+	key := fmt.Sprintf("board-lock:%s", roomID)
+	return unlockBoardScript.Run(ctx, rc.DB, []string{key}, token).Err()
+}
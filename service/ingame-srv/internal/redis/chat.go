@@ -5,8 +5,49 @@ import (
 	"encoding/json"
 	"fmt"
 	"ms4me/game_socket/internal/models"
+	"strings"
+
+	redisdb "github.com/redis/go-redis/v9"
 )
 
+// lobbyChatKey ключ общего чата лобби. Не начинается с chat:, чтобы не попадать в архивацию чатов игр
+const lobbyChatKey = "lobby-chat"
+
+// lobbyChatLen сколько последних сообщений общего чата хранится
+const lobbyChatLen = 200
+
+// lobbyWindowScript возвращает из общего чата только окно, нужное для страницы: без курсора
+// последние ARGV[3]+1 сообщений, с курсором ARGV[1] (before) или ARGV[2] (after) - сам курсор
+// и ARGV[3]+1 сообщений в направлении листания. Лишнее сообщение показывает, есть ли ещё.
+// Если курсора нет, возвращается пустой список
+var lobbyWindowScript = redisdb.NewScript(`
+local limit = tonumber(ARGV[3])
+local cursor = ARGV[1] ~= "" and ARGV[1] or ARGV[2]
+if cursor == "" then
+	return redis.call("LRANGE", KEYS[1], -(limit + 1), -1)
+end
+for i, raw in ipairs(redis.call("LRANGE", KEYS[1], 0, -1)) do
+	if cjson.decode(raw).id == cursor then
+		if ARGV[1] ~= "" then
+			return redis.call("LRANGE", KEYS[1], math.max(i - limit - 2, 0), i - 1)
+		end
+		return redis.call("LRANGE", KEYS[1], i - 1, i + limit)
+	end
+end
+return {}`)
+
+// replaceMessageScript заменяет неудалённое сообщение чата с id ARGV[1] на ARGV[2].
+// Поиск и замена идут одной командой, чтобы не задеть сообщения, добавленные за это время
+var replaceMessageScript = redisdb.NewScript(`
+for i, raw in ipairs(redis.call("LRANGE", KEYS[1], 0, -1)) do
+	local message = cjson.decode(raw)
+	if message.id == ARGV[1] and not message.deleted then
+		redis.call("LSET", KEYS[1], i - 1, ARGV[2])
+		return 1
+	end
+end
+return 0`)
+
 func (rc *Redis) CreateMessage(ctx context.Context, gameID string, message []byte) error {
 	key := fmt.Sprintf("chat:%s", gameID)
 
@@ -21,13 +62,39 @@ func (rc *Redis) CreateMessage(ctx context.Context, gameID string, message []byt
 }
 
 func (rc *Redis) ReadMessages(ctx context.Context, gameID string) ([]*models.Message, error) {
-	key := fmt.Sprintf("chat:%s", gameID)
+	return rc.readMessages(ctx, fmt.Sprintf("chat:%s", gameID))
+}
+
+// CreateLobbyMessage добавляет сообщение в общий чат лобби, в котором хранятся только последние lobbyChatLen сообщений
+func (rc *Redis) CreateLobbyMessage(ctx context.Context, message []byte) error {
+	pipe := rc.DB.TxPipeline()
+	pipe.RPush(ctx, lobbyChatKey, message)
+	pipe.LTrim(ctx, lobbyChatKey, -lobbyChatLen, -1)
+	pipe.Expire(ctx, lobbyChatKey, rc.msgTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ReadLobbyMessages читает из общего чата лобби окно вокруг курсора, из которого chat.Page соберёт страницу
+func (rc *Redis) ReadLobbyMessages(ctx context.Context, before, after string, limit int) ([]*models.Message, error) {
+	messagesBytes, err := lobbyWindowScript.Run(ctx, rc.DB, []string{lobbyChatKey}, before, after, limit).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeMessages(messagesBytes)
+}
 
+func (rc *Redis) readMessages(ctx context.Context, key string) ([]*models.Message, error) {
 	messagesBytes, err := rc.DB.LRange(ctx, key, 0, -1).Result()
 	if err != nil {
 		return nil, err
 	}
 
+	return decodeMessages(messagesBytes)
+}
+
+func decodeMessages(messagesBytes []string) ([]*models.Message, error) {
 	var messages []*models.Message
 	for _, msgBytes := range messagesBytes {
 		var msg models.Message
@@ -41,6 +108,40 @@ func (rc *Redis) ReadMessages(ctx context.Context, gameID string) ([]*models.Mes
 	return messages, nil
 }
 
+// GetMessage возвращает сообщение чата или ErrNil, если его нет или оно удалено
+func (rc *Redis) GetMessage(ctx context.Context, gameID string, messageID string) (*models.Message, error) {
+	messages, err := rc.ReadMessages(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range messages {
+		if message.ID == messageID && !message.Deleted {
+			return message, nil
+		}
+	}
+
+	return nil, ErrNil
+}
+
+// UpdateMessage сохраняет изменённое сообщение чата. Возвращает ErrNil, если сообщения уже нет или оно удалено
+func (rc *Redis) UpdateMessage(ctx context.Context, gameID string, message *models.Message) error {
+	key := fmt.Sprintf("chat:%s", gameID)
+
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	replaced, err := replaceMessageScript.Run(ctx, rc.DB, []string{key}, message.ID, messageBytes).Int()
+	if err != nil {
+		return err
+	}
+	if replaced == 0 {
+		return ErrNil
+	}
+
+	return nil
+}
+
 func (rc *Redis) DeleteChat(ctx context.Context, gameID string) error {
 	key := fmt.Sprintf("chat:%s", gameID)
 	return rc.DB.Del(ctx, key).Err()
@@ -59,3 +160,17 @@ func (rc *Redis) ChatExists(ctx context.Context, roomID string) (bool, error) {
 
 	return true, nil
 }
+
+// ChatIDs возвращает id игр, у которых в redis есть чат
+func (rc *Redis) ChatIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	iter := rc.DB.Scan(ctx, 0, "chat:*", 0).Iterator()
+	for iter.Next(ctx) {
+		ids = append(ids, strings.TrimPrefix(iter.Val(), "chat:"))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	dto_ws "ms4me/game_socket/internal/ws/dto"
+)
+
+// NextSeq выдаёт следующий порядковый номер события в комнате
+func (rc *Redis) NextSeq(ctx context.Context, roomID string) (int64, error) {
+	key := fmt.Sprintf("seq:%s", roomID)
+
+	seq, err := rc.DB.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	rc.DB.Expire(ctx, key, rc.msgTTL)
+
+	return seq, nil
+}
+
+// SaveEvent сохраняет событие в буфер комнаты, оставляя только последние eventsLen событий
+func (rc *Redis) SaveEvent(ctx context.Context, roomID string, event []byte) error {
+	key := fmt.Sprintf("events:%s", roomID)
+
+	pipe := rc.DB.TxPipeline()
+	pipe.RPush(ctx, key, event)
+	pipe.LTrim(ctx, key, -rc.eventsLen, -1)
+	pipe.Expire(ctx, key, rc.msgTTL)
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// ReadEventsAfter возвращает события комнаты с номером больше lastSeq.
+// Второе значение false, если часть событий после lastSeq уже вытеснена из буфера
+func (rc *Redis) ReadEventsAfter(ctx context.Context, roomID string, lastSeq int64) ([]*dto_ws.Response, bool, error) {
+	key := fmt.Sprintf("events:%s", roomID)
+
+	eventsBytes, err := rc.DB.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	events := make([]*dto_ws.Response, 0)
+	complete := true
+	for i, eventBytes := range eventsBytes {
+		var event dto_ws.Response
+		if err := json.Unmarshal([]byte(eventBytes), &event); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal event: %w", err)
+		}
+		if i == 0 && event.Seq > lastSeq+1 {
+			complete = false
+		}
+		if event.Seq > lastSeq {
+			events = append(events, &event)
+		}
+	}
+
+	return events, complete, nil
+}
+
+func (rc *Redis) DeleteEvents(ctx context.Context, roomID string) error {
+	return rc.DB.Del(ctx, fmt.Sprintf("events:%s", roomID), fmt.Sprintf("seq:%s", roomID)).Err()
+}
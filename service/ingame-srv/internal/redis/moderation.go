@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	redisdb "github.com/redis/go-redis/v9"
+)
+
+// takeTokenScript забирает жетон из корзины KEYS[1] вместимостью ARGV[1], которая пополняется
+// на один жетон каждые ARGV[2] мс. ARGV[3] - текущее время в мс. Возвращает 1, если жетон был
+var takeTokenScript = redisdb.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1]) or capacity
+local ts = tonumber(bucket[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) / interval)
+local taken = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	taken = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], capacity * interval)
+return taken`)
+
+// TakeChatToken забирает жетон отправки сообщения пользователя в канал.
+// Корзина вмещает burst жетонов и пополняется на один каждые interval
+func (rc *Redis) TakeChatToken(ctx context.Context, channel string, userID int64, burst int, interval time.Duration) (bool, error) {
+	key := fmt.Sprintf("chat-rate:%s:%d", channel, userID)
+	taken, err := takeTokenScript.Run(ctx, rc.DB, []string{key}, burst, interval.Milliseconds(), time.Now().UnixMilli()).Int()
+	if err != nil {
+		return false, err
+	}
+	return taken == 1, nil
+}
+
+// GetLastMessage возвращает отпечаток последнего сообщения пользователя в канале или пустую строку
+func (rc *Redis) GetLastMessage(ctx context.Context, channel string, userID int64) (string, error) {
+	key := fmt.Sprintf("chat-last:%s:%d", channel, userID)
+	digest, err := rc.DB.Get(ctx, key).Result()
+	if errors.Is(err, redisdb.Nil) {
+		return "", nil
+	}
+	return digest, err
+}
+
+// SetLastMessage запоминает отпечаток последнего сообщения пользователя в канале на ttl
+func (rc *Redis) SetLastMessage(ctx context.Context, channel string, userID int64, digest string, ttl time.Duration) error {
+	key := fmt.Sprintf("chat-last:%s:%d", channel, userID)
+	return rc.DB.Set(ctx, key, digest, ttl).Err()
+}
+
+// MuteUser запрещает пользователю писать в чат комнаты до её удаления
+func (rc *Redis) MuteUser(ctx context.Context, roomID string, userID int64) error {
+	return rc.DB.SAdd(ctx, fmt.Sprintf("chat-mute:%s", roomID), userID).Err()
+}
+
+func (rc *Redis) UnmuteUser(ctx context.Context, roomID string, userID int64) error {
+	return rc.DB.SRem(ctx, fmt.Sprintf("chat-mute:%s", roomID), userID).Err()
+}
+
+func (rc *Redis) IsMuted(ctx context.Context, roomID string, userID int64) (bool, error) {
+	return rc.DB.SIsMember(ctx, fmt.Sprintf("chat-mute:%s", roomID), userID).Result()
+}
@@ -15,11 +15,12 @@ var (
 )
 
 type Redis struct {
-	DB     *redisdb.Client
-	msgTTL time.Duration
+	DB        *redisdb.Client
+	msgTTL    time.Duration
+	eventsLen int64
 }
 
-func New(ctx context.Context, cfg *config.RedisConfig, msgTTL time.Duration) (*Redis, error) {
+func New(ctx context.Context, cfg *config.RedisConfig, msgTTL time.Duration, eventsLen int) (*Redis, error) {
 	client := redisdb.NewClient(&redisdb.Options{
 		Addr:     cfg.Host + ":" + strconv.Itoa(cfg.Port),
 		Password: cfg.Password,
@@ -31,7 +32,7 @@ func New(ctx context.Context, cfg *config.RedisConfig, msgTTL time.Duration) (*R
 		return nil, err
 	}
 
-	return &Redis{client, msgTTL}, nil
+	return &Redis{client, msgTTL, int64(eventsLen)}, nil
 }
 
 func (r *Redis) Stop(ctx context.Context) error {
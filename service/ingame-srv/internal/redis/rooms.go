@@ -3,16 +3,69 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"ms4me/game_socket/internal/models"
+	"ms4me/game_socket/internal/service/game"
 	"strconv"
+
+	redisdb "github.com/redis/go-redis/v9"
 )
 
 const PUBLIC_QUEUE = "queue"
 
+// participantRecord представление участника комнаты в redis, поле хранится в бинарном виде
+type participantRecord struct {
+	*models.RoomParticipant
+	Field json.RawMessage `json:"field,omitempty"`
+}
+
+func marshalParticipant(meta *models.RoomParticipant) ([]byte, error) {
+	record := participantRecord{RoomParticipant: meta}
+	if meta.Field != nil {
+		encoded, err := json.Marshal(meta.Field.Encode())
+		if err != nil {
+			return nil, err
+		}
+		record.Field = encoded
+	}
+	return json.Marshal(record)
+}
+
+func unmarshalParticipant(data []byte) (*models.RoomParticipant, error) {
+	record := participantRecord{RoomParticipant: &models.RoomParticipant{}}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	if len(record.Field) == 0 || string(record.Field) == "null" {
+		return record.RoomParticipant, nil
+	}
+
+	// Участники, сохранённые до бинарного формата, хранят поле json-объектом
+	if record.Field[0] == '{' {
+		field, err := game.DecodeJSONField(record.Field)
+		if err != nil {
+			return nil, err
+		}
+		record.RoomParticipant.Field = field
+		return record.RoomParticipant, nil
+	}
+
+	var encoded []byte
+	if err := json.Unmarshal(record.Field, &encoded); err != nil {
+		return nil, err
+	}
+	field, err := game.DecodeField(encoded)
+	if err != nil {
+		return nil, err
+	}
+	record.RoomParticipant.Field = field
+	return record.RoomParticipant, nil
+}
+
 func (rc *Redis) AddClientToChannel(ctx context.Context, channel string, userID int64, meta *models.RoomParticipant) error {
 	key := fmt.Sprintf("room:%s", channel)
-	data, err := json.Marshal(meta)
+	data, err := marshalParticipant(meta)
 	if err != nil {
 		return err
 	}
@@ -24,9 +77,33 @@ func (rc *Redis) RemoveClientFromChannel(ctx context.Context, channel string, us
 	return rc.DB.HDel(ctx, key, fmt.Sprintf("%d", userID)).Err()
 }
 
+// DeleteRoom удаляет участников комнаты, её настройки, общее поле совместной игры и заглушения в чате
 func (rc *Redis) DeleteRoom(ctx context.Context, channel string) error {
-	key := fmt.Sprintf("room:%s", channel)
-	return rc.DB.Del(ctx, key).Err()
+	return rc.DB.Del(ctx, fmt.Sprintf("room:%s", channel), fmt.Sprintf("room-settings:%s", channel), fmt.Sprintf("room-finished:%s", channel),
+		fmt.Sprintf("board:%s", channel), fmt.Sprintf("board-lock:%s", channel), fmt.Sprintf("chat-mute:%s", channel)).Err()
+}
+
+// MarkRoomFinished отмечает игру законченной. Возвращает false, если её уже закончили раньше
+func (rc *Redis) MarkRoomFinished(ctx context.Context, roomID string) (bool, error) {
+	key := fmt.Sprintf("room-finished:%s", roomID)
+	return rc.DB.SetNX(ctx, key, 1, 0).Result()
+}
+
+// SaveRoomSettings сохраняет настройки начатой игры, чтобы ходы не запрашивали их в game-srv
+func (rc *Redis) SaveRoomSettings(ctx context.Context, roomID string, settings []byte) error {
+	key := fmt.Sprintf("room-settings:%s", roomID)
+	return rc.DB.Set(ctx, key, settings, 0).Err()
+}
+
+// GetRoomSettings возвращает сохранённые настройки игры или ErrNil, если их ещё нет
+func (rc *Redis) GetRoomSettings(ctx context.Context, roomID string) ([]byte, error) {
+	key := fmt.Sprintf("room-settings:%s", roomID)
+
+	data, err := rc.DB.Get(ctx, key).Bytes()
+	if errors.Is(err, redisdb.Nil) {
+		return nil, ErrNil
+	}
+	return data, err
 }
 
 func (rc *Redis) GetClientsInChannel(ctx context.Context, channel string) (map[string]*models.RoomParticipant, error) {
@@ -38,9 +115,9 @@ func (rc *Redis) GetClientsInChannel(ctx context.Context, channel string) (map[s
 
 	clients := make(map[string]*models.RoomParticipant)
 	for uid, raw := range result {
-		var meta *models.RoomParticipant
-		if err := json.Unmarshal([]byte(raw), &meta); err != nil {
-			continue
+		meta, err := unmarshalParticipant([]byte(raw))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding participant %s: %w", uid, err)
 		}
 		clients[uid] = meta
 	}
@@ -77,16 +154,22 @@ func (rc *Redis) GetUsersInChannel(ctx context.Context, channel string) ([]int,
 	return users, nil
 }
 
+// GetClientInChannel возвращает участника комнаты или ErrNil, если его в комнате нет
 func (rc *Redis) GetClientInChannel(ctx context.Context, roomID string, userID int64) (*models.RoomParticipant, error) {
 	key := fmt.Sprintf("room:%s", roomID)
 	result, err := rc.DB.HGet(ctx, key, fmt.Sprintf("%d", userID)).Result()
+	if errors.Is(err, redisdb.Nil) {
+		return nil, ErrNil
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	var roomParticipant models.RoomParticipant
-	if err := json.Unmarshal([]byte(result), &roomParticipant); err != nil {
-		return nil, err
-	}
-	return &roomParticipant, nil
+	return unmarshalParticipant([]byte(result))
+}
+
+// IsClientInChannel проверяет, что пользователь - участник комнаты
+func (rc *Redis) IsClientInChannel(ctx context.Context, roomID string, userID int64) (bool, error) {
+	key := fmt.Sprintf("room:%s", roomID)
+	return rc.DB.HExists(ctx, key, fmt.Sprintf("%d", userID)).Result()
 }
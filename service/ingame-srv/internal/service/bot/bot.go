@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	storage "ms4me/game_socket/internal/redis"
+	"ms4me/game_socket/internal/service/play"
+	gameclient "ms4me/game_socket/pkg/game_client"
+	"time"
+
+	"github.com/jacute/prettylogger"
+)
+
+// maxMoves ограничение на количество ходов бота, чтобы горутина не жила вечно при ошибках
+const maxMoves = 500
+
+// Runner запускает ботов, добавленных в игру. Каждый бот ходит в своей горутине через тот же play, что и люди
+type Runner struct {
+	log        *slog.Logger
+	redis      *storage.Redis
+	play       *play.Play
+	gameClient *gameclient.GameClient
+}
+
+func New(log *slog.Logger, redis *storage.Redis, play *play.Play, gc *gameclient.GameClient) *Runner {
+	return &Runner{
+		log:        log,
+		redis:      redis,
+		play:       play,
+		gameClient: gc,
+	}
+}
+
+// Start запускает всех ботов комнаты после начала игры
+func (r *Runner) Start(gameID string) {
+	const op = "bot.Start"
+	log := r.log.With(slog.String("op", op), slog.String("game_id", gameID))
+
+	participants, err := r.redis.GetClientsInChannel(context.Background(), gameID)
+	if err != nil {
+		log.Error("error getting room participants", prettylogger.Err(err))
+		return
+	}
+	for _, participant := range participants {
+		if participant.Bot == "" {
+			continue
+		}
+		prof, ok := profiles[Level(participant.Bot)]
+		if !ok {
+			log.Warn("unknown bot level", slog.String("level", participant.Bot))
+			continue
+		}
+		go r.run(gameID, participant.ID, prof)
+	}
+}
+
+func (r *Runner) run(gameID string, userID int64, prof profile) {
+	const op = "bot.run"
+	log := r.log.With(slog.String("op", op), slog.String("game_id", gameID), slog.Int64("user_id", userID))
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(time.Now().UnixNano() + userID))
+
+	for i := 0; i < maxMoves; i++ {
+		// Небольшой разброс паузы, чтобы ходы бота не выглядели механическими
+		time.Sleep(prof.thinkDelay/2 + time.Duration(rng.Int63n(int64(prof.thinkDelay))))
+
+		status, err := r.gameClient.GetStatus(gameID)
+		if err != nil {
+			log.Error("error getting game status", prettylogger.Err(err))
+			return
+		}
+		if status != "started" {
+			return
+		}
+
+		participant, err := r.redis.GetClientInChannel(ctx, gameID, userID)
+		if err != nil {
+			log.Error("error getting bot participant", prettylogger.Err(err))
+			return
+		}
+		// В совместной игре бот ходит на общем поле комнаты
+		if participant.Field == nil {
+			board, err := r.redis.GetBoard(ctx, gameID)
+			if err != nil && !errors.Is(err, storage.ErrNil) {
+				log.Error("error getting shared board", prettylogger.Err(err))
+				return
+			}
+			participant.Field = board
+		}
+		if participant.Field != nil && (participant.Field.MineIsOpen || participant.Field.IsWin()) {
+			return
+		}
+
+		m, ok := chooseMove(participant.Field, prof, rng)
+		if !ok {
+			return
+		}
+		if m.flag {
+			err = r.play.Flag(ctx, gameID, userID, m.Row, m.Col)
+		} else {
+			err = r.play.OpenCell(ctx, gameID, userID, m.Row, m.Col)
+		}
+		if errors.Is(err, play.ErrRoomNotFound) || errors.Is(err, play.ErrNotParticipant) || errors.Is(err, play.ErrBoardFinished) {
+			return
+		}
+		if err != nil {
+			log.Warn("bot move failed", slog.Int("row", m.Row), slog.Int("col", m.Col), prettylogger.Err(err))
+		}
+	}
+	log.Warn("bot reached move limit")
+}
@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"math/rand"
+	"ms4me/game_socket/internal/service/game"
+	"time"
+)
+
+type Level string
+
+const (
+	Easy   Level = "easy"
+	Medium Level = "medium"
+	Hard   Level = "hard"
+)
+
+// profile параметры поведения бота
+type profile struct {
+	errorRate  float64       // вероятность сделать случайный ход вместо вычисленного решателем
+	thinkDelay time.Duration // пауза перед каждым ходом
+	flags      bool          // ставит ли бот флаги на найденные мины
+}
+
+var profiles = map[Level]profile{
+	Easy:   {errorRate: 0.2, thinkDelay: 3 * time.Second},
+	Medium: {errorRate: 0.05, thinkDelay: 2 * time.Second, flags: true},
+	Hard:   {errorRate: 0, thinkDelay: time.Second, flags: true},
+}
+
+// IsValidLevel проверяет, что уровень бота существует
+func IsValidLevel(level string) bool {
+	_, ok := profiles[Level(level)]
+	return ok
+}
+
+// move ход бота: открыть клетку или поставить флаг
+type move struct {
+	game.Point
+	flag bool
+}
+
+// chooseMove выбирает следующий ход по видимому состоянию поля.
+// С вероятностью errorRate бот открывает случайную клетку, иначе ставит флаги на найденные мины,
+// открывает безопасные клетки и угадывает, только если решатель ничего не нашёл
+func chooseMove(f *game.Field, prof profile, rng *rand.Rand) (move, bool) {
+	// Поля ещё нет, первый ход безопасен в любую клетку
+	if f == nil {
+		empty := game.NewField()
+		return move{Point: game.Point{Row: rng.Intn(empty.Rows), Col: rng.Intn(empty.Cols)}}, true
+	}
+
+	safe, mines := game.Analyze(f)
+	if rng.Float64() >= prof.errorRate {
+		if prof.flags {
+			for _, p := range mines {
+				if f.Grid[p.Row][p.Col].Value != game.FLAG {
+					return move{Point: p, flag: true}, true
+				}
+			}
+		}
+		for _, p := range safe {
+			if f.Grid[p.Row][p.Col].Value != game.FLAG {
+				return move{Point: p}, true
+			}
+		}
+	}
+
+	candidates := make([]game.Point, 0)
+	for row := 0; row < f.Rows; row++ {
+		for col := 0; col < f.Cols; col++ {
+			p := game.Point{Row: row, Col: col}
+			cell := f.Grid[row][col]
+			if cell.IsOpen || cell.Value == game.FLAG || containsPoint(mines, p) {
+				continue
+			}
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		return move{}, false
+	}
+	return move{Point: candidates[rng.Intn(len(candidates))]}, true
+}
+
+func containsPoint(points []game.Point, p game.Point) bool {
+	for _, point := range points {
+		if point == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"math/rand"
+	"ms4me/game_socket/internal/service/game"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChooseMoveWithoutField(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	empty := game.NewField()
+	for i := 0; i < 100; i++ {
+		m, ok := chooseMove(nil, profiles[Hard], rng)
+		require.True(t, ok)
+		require.False(t, m.flag)
+		require.True(t, m.Row >= 0 && m.Row < empty.Rows)
+		require.True(t, m.Col >= 0 && m.Col < empty.Cols)
+	}
+}
+
+func TestChooseMoveFollowsSolver(t *testing.T) {
+	testCases := []struct {
+		name  string
+		level Level
+	}{
+		{name: "medium", level: Medium},
+		{name: "hard", level: Hard},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prof := profiles[tc.level]
+			prof.errorRate = 0 // проверяем только ходы решателя
+			for seed := int64(1); seed <= 20; seed++ {
+				rng := rand.New(rand.NewSource(seed))
+				f := game.CreateField(seed, game.Square, 0, 0)
+				require.NoError(t, f.OpenCell(0, 0))
+
+				for !f.MineIsOpen && !f.IsWin() {
+					safe, _ := game.Analyze(f)
+					m, ok := chooseMove(f, prof, rng)
+					require.True(t, ok)
+					if m.flag {
+						require.True(t, f.Grid[m.Row][m.Col].IsMine(), "flag on a safe cell")
+						require.NoError(t, f.SetFlag(m.Row, m.Col))
+						continue
+					}
+					if len(safe) > 0 {
+						require.False(t, f.Grid[m.Row][m.Col].IsMine(), "deduced cell is a mine")
+					}
+					require.NoError(t, f.OpenCell(m.Row, m.Col))
+				}
+			}
+		})
+	}
+}
+
+func TestChooseMoveNoCandidates(t *testing.T) {
+	f := game.CreateField(1, game.Square, 0, 0)
+	for row := 0; row < f.Rows; row++ {
+		for col := 0; col < f.Cols; col++ {
+			if !f.Grid[row][col].IsMine() && !f.Grid[row][col].IsOpen {
+				require.NoError(t, f.OpenCell(row, col))
+			}
+		}
+	}
+	for row := 0; row < f.Rows; row++ {
+		for col := 0; col < f.Cols; col++ {
+			if f.Grid[row][col].IsMine() {
+				require.NoError(t, f.SetFlag(row, col))
+			}
+		}
+	}
+	_, ok := chooseMove(f, profiles[Easy], rand.New(rand.NewSource(1)))
+	require.False(t, ok)
+}
+
+func TestIsValidLevel(t *testing.T) {
+	require.True(t, IsValidLevel("easy"))
+	require.True(t, IsValidLevel("medium"))
+	require.True(t, IsValidLevel("hard"))
+	require.False(t, IsValidLevel("expert"))
+	require.False(t, IsValidLevel(""))
+}
@@ -0,0 +1,81 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+	storage "ms4me/game_socket/internal/redis"
+	gameclient "ms4me/game_socket/pkg/game_client"
+	"time"
+
+	"github.com/jacute/prettylogger"
+)
+
+// defaultArchiveInterval период архивации, если он не задан в конфиге
+const defaultArchiveInterval = 5 * time.Minute
+
+// Archiver переносит чаты игр из redis в game-srv, где они хранятся дольше ttl сообщений
+type Archiver struct {
+	log        *slog.Logger
+	redis      *storage.Redis
+	gameClient *gameclient.GameClient
+	interval   time.Duration
+	stopCh     chan struct{}
+}
+
+func New(log *slog.Logger, redis *storage.Redis, gc *gameclient.GameClient, interval time.Duration) *Archiver {
+	if interval <= 0 {
+		interval = defaultArchiveInterval
+	}
+	return &Archiver{
+		log:        log,
+		redis:      redis,
+		gameClient: gc,
+		interval:   interval,
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Archive сохраняет текущий чат игры в game-srv. Повторная архивация того же чата безопасна
+func (a *Archiver) Archive(ctx context.Context, gameID string) error {
+	messages, err := a.redis.ReadMessages(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return a.gameClient.ArchiveChat(gameID, messages)
+}
+
+// Run периодически архивирует все чаты из redis, чтобы длинные игры не теряли историю по ttl
+func (a *Archiver) Run() {
+	const op = "chat.Run"
+	log := a.log.With(slog.String("op", op))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t := time.NewTicker(a.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			ids, err := a.redis.ChatIDs(ctx)
+			if err != nil {
+				log.Error("error getting chats from redis", prettylogger.Err(err))
+				continue
+			}
+			for _, id := range ids {
+				if err := a.Archive(ctx, id); err != nil {
+					log.Error("error archiving chat", slog.String("game_id", id), prettylogger.Err(err))
+				}
+			}
+		case <-a.stopCh:
+			return
+		}
+	}
+}
+
+func (a *Archiver) Stop() {
+	close(a.stopCh)
+}
@@ -0,0 +1,70 @@
+package chat
+
+import (
+	"errors"
+	"ms4me/game_socket/internal/models"
+	"slices"
+	"time"
+)
+
+// EditWindow сколько времени после отправки автор может изменить или удалить сообщение
+const EditWindow = 5 * time.Minute
+
+var (
+	ErrMessageNotFound   = errors.New("сообщение не найдено")
+	ErrNotMessageAuthor  = errors.New("изменять можно только свои сообщения")
+	ErrEditWindowExpired = errors.New("время на изменение сообщения истекло")
+)
+
+// Page возвращает страницу чата в порядке отправки. Без курсоров отдаются последние limit сообщений,
+// с before - limit сообщений перед ним, с after - limit сообщений после него.
+// Удалённые сообщения не попадают в страницу, но могут быть курсором.
+// Второе значение сообщает, есть ли ещё сообщения дальше в направлении листания
+func Page(messages []*models.Message, before, after string, limit int) ([]*models.Message, bool, error) {
+	start, step := len(messages)-1, -1
+	if before != "" {
+		i := slices.IndexFunc(messages, func(m *models.Message) bool { return m.ID == before })
+		if i == -1 {
+			return nil, false, ErrMessageNotFound
+		}
+		start = i - 1
+	}
+	if after != "" {
+		i := slices.IndexFunc(messages, func(m *models.Message) bool { return m.ID == after })
+		if i == -1 {
+			return nil, false, ErrMessageNotFound
+		}
+		start, step = i+1, 1
+	}
+
+	page := make([]*models.Message, 0, limit)
+	i := start
+	for ; i >= 0 && i < len(messages) && len(page) < limit; i += step {
+		if !messages[i].Deleted {
+			page = append(page, messages[i])
+		}
+	}
+	hasMore := false
+	for ; i >= 0 && i < len(messages); i += step {
+		if !messages[i].Deleted {
+			hasMore = true
+			break
+		}
+	}
+	if step < 0 {
+		slices.Reverse(page)
+	}
+
+	return page, hasMore, nil
+}
+
+// CanModify проверяет, что пользователь может изменить или удалить сообщение в момент now
+func CanModify(message *models.Message, userID int64, now time.Time) error {
+	if message.CreatorID != userID {
+		return ErrNotMessageAuthor
+	}
+	if now.Sub(message.CreatedAt) > EditWindow {
+		return ErrEditWindowExpired
+	}
+	return nil
+}
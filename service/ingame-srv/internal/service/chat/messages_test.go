@@ -0,0 +1,113 @@
+package chat
+
+import (
+	"ms4me/game_socket/internal/models"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPage(t *testing.T) {
+	messages := make([]*models.Message, 0, 6)
+	for i := range 6 {
+		messages = append(messages, &models.Message{ID: strconv.Itoa(i), Deleted: i == 3})
+	}
+
+	testCases := []struct {
+		name    string
+		before  string
+		after   string
+		limit   int
+		ids     []string
+		hasMore bool
+		err     error
+	}{
+		{name: "latest", limit: 2, ids: []string{"4", "5"}, hasMore: true},
+		{name: "whole chat", limit: 10, ids: []string{"0", "1", "2", "4", "5"}},
+		{name: "before skips deleted", before: "5", limit: 2, ids: []string{"2", "4"}, hasMore: true},
+		{name: "before first page", before: "2", limit: 2, ids: []string{"0", "1"}},
+		{name: "after", after: "0", limit: 2, ids: []string{"1", "2"}, hasMore: true},
+		{name: "after deleted cursor", after: "3", limit: 2, ids: []string{"4", "5"}},
+		{name: "after last", after: "5", limit: 2, ids: []string{}},
+		{name: "unknown cursor", before: "42", limit: 2, err: ErrMessageNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, hasMore, err := Page(messages, tc.before, tc.after, tc.limit)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			ids := make([]string, 0, len(page))
+			for _, message := range page {
+				ids = append(ids, message.ID)
+			}
+			require.Equal(t, tc.ids, ids)
+			require.Equal(t, tc.hasMore, hasMore)
+		})
+	}
+}
+
+func TestCanModify(t *testing.T) {
+	now := time.Now()
+	message := &models.Message{CreatorID: 1, CreatedAt: now.Add(-time.Minute)}
+
+	require.NoError(t, CanModify(message, 1, now))
+	require.ErrorIs(t, CanModify(message, 2, now), ErrNotMessageAuthor)
+	require.ErrorIs(t, CanModify(message, 1, now.Add(EditWindow)), ErrEditWindowExpired)
+}
+
+func TestPageLobbyWindow(t *testing.T) {
+	// история общего чата без удалённых сообщений, window режет её так же, как lobbyWindowScript в redis
+	history := make([]*models.Message, 0, 10)
+	for i := range 10 {
+		history = append(history, &models.Message{ID: strconv.Itoa(i)})
+	}
+	window := func(before, after string, limit int) []*models.Message {
+		if before == "" && after == "" {
+			return history[max(len(history)-limit-1, 0):]
+		}
+		cursor := before + after
+		i := slices.IndexFunc(history, func(m *models.Message) bool { return m.ID == cursor })
+		if i == -1 {
+			return nil
+		}
+		if before != "" {
+			return history[max(i-limit-1, 0) : i+1]
+		}
+		return history[i:min(i+limit+2, len(history))]
+	}
+
+	testCases := []struct {
+		name   string
+		before string
+		after  string
+		limit  int
+	}{
+		{name: "latest", limit: 3},
+		{name: "latest whole chat", limit: 10},
+		{name: "latest more than chat", limit: 20},
+		{name: "before", before: "6", limit: 3},
+		{name: "before reaches start", before: "3", limit: 3},
+		{name: "before first", before: "0", limit: 3},
+		{name: "after", after: "2", limit: 3},
+		{name: "after reaches end", after: "6", limit: 3},
+		{name: "after last", after: "9", limit: 3},
+		{name: "unknown cursor", after: "42", limit: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wantPage, wantMore, wantErr := Page(history, tc.before, tc.after, tc.limit)
+			page, hasMore, err := Page(window(tc.before, tc.after, tc.limit), tc.before, tc.after, tc.limit)
+			require.Equal(t, wantErr, err)
+			require.Equal(t, wantPage, page)
+			require.Equal(t, wantMore, hasMore)
+		})
+	}
+}
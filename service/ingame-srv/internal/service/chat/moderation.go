@@ -0,0 +1,178 @@
+package chat
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// messageBurst сколько сообщений подряд можно отправить в канал, прежде чем сработает ограничение
+const messageBurst = 5
+
+// messageInterval за сколько восстанавливается одно сообщение из messageBurst
+const messageInterval = 2 * time.Second
+
+// emoteBurst и emoteInterval ограничение частоты эмоций, отдельное от сообщений чата
+const emoteBurst = 3
+const emoteInterval = 3 * time.Second
+
+// duplicateWindow в течение какого времени повтор последнего сообщения считается флудом
+const duplicateWindow = 30 * time.Second
+
+// LobbyChannel канал общего чата лобби в ограничениях отправки
+const LobbyChannel = "lobby"
+
+var (
+	ErrMuted            = errors.New("владелец игры запретил вам писать в чат")
+	ErrTooManyMessages  = errors.New("слишком много сообщений, подождите немного")
+	ErrDuplicateMessage = errors.New("это сообщение уже отправлено")
+)
+
+// WordFilter обрабатывает текст сообщения перед отправкой
+type WordFilter interface {
+	Filter(text string) string
+}
+
+// ModerationStorage хранит заглушения, корзины частоты отправки и отпечатки последних сообщений
+type ModerationStorage interface {
+	IsMuted(ctx context.Context, roomID string, userID int64) (bool, error)
+	TakeChatToken(ctx context.Context, channel string, userID int64, burst int, interval time.Duration) (bool, error)
+	GetLastMessage(ctx context.Context, channel string, userID int64) (string, error)
+	SetLastMessage(ctx context.Context, channel string, userID int64, digest string, ttl time.Duration) error
+}
+
+// Moderator проверяет сообщения чата: заглушения, частоту отправки, повторы и запрещённые слова
+type Moderator struct {
+	redis  ModerationStorage
+	filter WordFilter
+}
+
+func NewModerator(redis ModerationStorage, filter WordFilter) *Moderator {
+	return &Moderator{redis: redis, filter: filter}
+}
+
+// Check проверяет, что пользователь может отправить text в канал, и возвращает текст после фильтра слов.
+// Канал - id комнаты игры или LobbyChannel. После сохранения сообщения нужно вызвать Remember
+func (m *Moderator) Check(ctx context.Context, channel string, userID int64, text string) (string, error) {
+	muted, err := m.redis.IsMuted(ctx, channel, userID)
+	if err != nil {
+		return "", err
+	}
+	if muted {
+		return "", ErrMuted
+	}
+
+	allowed, err := m.redis.TakeChatToken(ctx, channel, userID, messageBurst, messageInterval)
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		return "", ErrTooManyMessages
+	}
+
+	previous, err := m.redis.GetLastMessage(ctx, channel, userID)
+	if err != nil {
+		return "", err
+	}
+	if previous == messageDigest(text) {
+		return "", ErrDuplicateMessage
+	}
+
+	return m.filter.Filter(text), nil
+}
+
+// Remember запоминает отправленное сообщение для проверки повторов. Вызывается только после сохранения сообщения,
+// чтобы неудачная отправка не мешала повторить её
+func (m *Moderator) Remember(ctx context.Context, channel string, userID int64, text string) error {
+	return m.redis.SetLastMessage(ctx, channel, userID, messageDigest(text), duplicateWindow)
+}
+
+// CheckEdit проверяет, что пользователь может изменить своё сообщение на text, и возвращает текст после фильтра слов.
+// Правка не новое сообщение, поэтому ограничение частоты и проверка повторов к ней не применяются
+func (m *Moderator) CheckEdit(ctx context.Context, channel string, userID int64, text string) (string, error) {
+	muted, err := m.redis.IsMuted(ctx, channel, userID)
+	if err != nil {
+		return "", err
+	}
+	if muted {
+		return "", ErrMuted
+	}
+
+	return m.filter.Filter(text), nil
+}
+
+// CheckEmote проверяет, что участник может отправить эмоцию в комнату. Заглушённым эмоции тоже запрещены
+func (m *Moderator) CheckEmote(ctx context.Context, roomID string, userID int64) error {
+	muted, err := m.redis.IsMuted(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if muted {
+		return ErrMuted
+	}
+
+	allowed, err := m.redis.TakeChatToken(ctx, "emote:"+roomID, userID, emoteBurst, emoteInterval)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrTooManyMessages
+	}
+	return nil
+}
+
+// BannedWords скрывает звёздочками слова из списка без учёта регистра
+type BannedWords struct {
+	words map[string]struct{}
+}
+
+func NewBannedWords(words []string) *BannedWords {
+	banned := &BannedWords{words: make(map[string]struct{}, len(words))}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			banned.words[word] = struct{}{}
+		}
+	}
+	return banned
+}
+
+func (b *BannedWords) Filter(text string) string {
+	if len(b.words) == 0 {
+		return text
+	}
+
+	runes := []rune(text)
+	var filtered strings.Builder
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			filtered.WriteRune(runes[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		word := string(runes[i:j])
+		if _, ok := b.words[strings.ToLower(word)]; ok {
+			word = strings.Repeat("*", j-i)
+		}
+		filtered.WriteString(word)
+		i = j
+	}
+	return filtered.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// messageDigest отпечаток сообщения для проверки повторов, регистр и пробелы по краям не учитываются
+func messageDigest(text string) string {
+	digest := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(text))))
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,127 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBannedWords(t *testing.T) {
+	filter := NewBannedWords([]string{"лох", " Noob ", ""})
+
+	testCases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "clean", text: "хорошая игра", want: "хорошая игра"},
+		{name: "banned word", text: "ты лох", want: "ты ***"},
+		{name: "case insensitive", text: "NOOB!", want: "****!"},
+		{name: "part of word", text: "noobish", want: "noobish"},
+		{name: "several words", text: "лох,noob лох", want: "***,**** ***"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, filter.Filter(tc.text))
+		})
+	}
+}
+
+func TestMessageDigest(t *testing.T) {
+	testCases := []struct {
+		name  string
+		first string
+		other string
+		same  bool
+	}{
+		{name: "equal", first: "gg", other: "gg", same: true},
+		{name: "case only", first: "GG", other: "gg", same: true},
+		{name: "trailing spaces", first: " gg  ", other: "gg", same: true},
+		{name: "different", first: "gg", other: "wp", same: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.same, messageDigest(tc.first) == messageDigest(tc.other))
+		})
+	}
+}
+
+// fakeModerationStorage корзины без пополнения: за время теста ни один жетон не восстанавливается
+type fakeModerationStorage struct {
+	muted     map[string]bool
+	taken     map[string]int
+	intervals map[string]time.Duration
+}
+
+func newFakeModerationStorage() *fakeModerationStorage {
+	return &fakeModerationStorage{
+		muted:     make(map[string]bool),
+		taken:     make(map[string]int),
+		intervals: make(map[string]time.Duration),
+	}
+}
+
+func (s *fakeModerationStorage) IsMuted(_ context.Context, roomID string, userID int64) (bool, error) {
+	return s.muted[fmt.Sprintf("%s:%d", roomID, userID)], nil
+}
+
+func (s *fakeModerationStorage) TakeChatToken(_ context.Context, channel string, userID int64, burst int, interval time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s:%d", channel, userID)
+	s.intervals[key] = interval
+	if s.taken[key] >= burst {
+		return false, nil
+	}
+	s.taken[key]++
+	return true, nil
+}
+
+func (s *fakeModerationStorage) GetLastMessage(context.Context, string, int64) (string, error) {
+	return "", nil
+}
+
+func (s *fakeModerationStorage) SetLastMessage(context.Context, string, int64, string, time.Duration) error {
+	return nil
+}
+
+func TestCheckEmote(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("throttled after burst", func(t *testing.T) {
+		storage := newFakeModerationStorage()
+		moderator := NewModerator(storage, NewBannedWords(nil))
+
+		for range emoteBurst {
+			require.NoError(t, moderator.CheckEmote(ctx, "room", 1))
+		}
+		require.ErrorIs(t, moderator.CheckEmote(ctx, "room", 1), ErrTooManyMessages)
+		require.Equal(t, emoteInterval, storage.intervals["emote:room:1"])
+
+		// у другого участника и в другой комнате свои корзины
+		require.NoError(t, moderator.CheckEmote(ctx, "room", 2))
+		require.NoError(t, moderator.CheckEmote(ctx, "other", 1))
+	})
+
+	t.Run("separate from chat messages", func(t *testing.T) {
+		moderator := NewModerator(newFakeModerationStorage(), NewBannedWords(nil))
+
+		for range emoteBurst {
+			require.NoError(t, moderator.CheckEmote(ctx, "room", 1))
+		}
+		_, err := moderator.Check(ctx, "room", 1, "gg")
+		require.NoError(t, err)
+	})
+
+	t.Run("muted", func(t *testing.T) {
+		storage := newFakeModerationStorage()
+		storage.muted["room:1"] = true
+		moderator := NewModerator(storage, NewBannedWords(nil))
+
+		require.ErrorIs(t, moderator.CheckEmote(ctx, "room", 1), ErrMuted)
+		require.Zero(t, storage.taken["emote:room:1"])
+	})
+}
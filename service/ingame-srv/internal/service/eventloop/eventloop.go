@@ -7,6 +7,9 @@ import (
 	"log/slog"
 	"ms4me/game_socket/internal/models"
 	storage "ms4me/game_socket/internal/redis"
+	"ms4me/game_socket/internal/service/bot"
+	"ms4me/game_socket/internal/service/chat"
+	"ms4me/game_socket/internal/service/play"
 	dto_ws "ms4me/game_socket/internal/ws/dto"
 	ws "ms4me/game_socket/internal/ws/server"
 	"sync"
@@ -27,13 +30,19 @@ type EventLoop struct {
 	ws     *ws.Server
 	redis  *storage.Redis
 	pubsub *redis.PubSub
+	bots   *bot.Runner
+	chat   *chat.Archiver
+	play   *play.Play
 }
 
-func New(log *slog.Logger, ws *ws.Server, redis *storage.Redis) *EventLoop {
+func New(log *slog.Logger, ws *ws.Server, redis *storage.Redis, bots *bot.Runner, chat *chat.Archiver, play *play.Play) *EventLoop {
 	return &EventLoop{
 		log:    log,
 		ws:     ws,
 		redis:  redis,
+		bots:   bots,
+		chat:   chat,
+		play:   play,
 		pubsub: redis.DB.Subscribe(context.Background(), storage.PUBLIC_QUEUE),
 	}
 }
@@ -93,6 +102,7 @@ func (s *EventLoop) EventLoop() {
 				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
 				return
 			}
+			s.stamp(eventCtx, log, event.GameID, resp)
 			if event.IsPublic {
 				go s.ws.BroadcastEvent(resp)
 			}
@@ -113,11 +123,16 @@ func (s *EventLoop) EventLoop() {
 				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
 				return
 			}
+			s.stamp(eventCtx, log, event.GameID, resp)
 			err = s.redis.DeleteRoom(eventCtx, event.GameID)
 			if err != nil {
 				log.Error("error deleting channel", slog.Any("event", event), prettylogger.Err(err))
 				continue
 			}
+			err = s.redis.DeleteEvents(eventCtx, event.GameID)
+			if err != nil {
+				log.Error("error deleting room events", slog.Any("event", event), prettylogger.Err(err))
+			}
 
 			// При удалении игры удаляем и чат
 			exists, err := s.redis.ChatExists(eventCtx, event.GameID)
@@ -146,10 +161,19 @@ func (s *EventLoop) EventLoop() {
 				s.ws.DisconnectRoom(event.GameID, users)
 			}()
 		case models.TypeJoinGame:
+			var joinEvent models.JoinEvent
+			if len(event.Payload) > 0 {
+				err := json.Unmarshal(event.Payload, &joinEvent)
+				if err != nil {
+					log.Error("error unmarshalling event", slog.Any("event", event), prettylogger.Err(err))
+					continue
+				}
+			}
 			payloadMarshalled, err := json.Marshal(map[string]any{
 				"id":       event.GameID,
 				"user_id":  event.UserID,
 				"username": event.Username,
+				"bot":      joinEvent.Bot,
 			})
 			if err != nil {
 				log.Error("error marshalling event", slog.Any("event", event))
@@ -165,10 +189,12 @@ func (s *EventLoop) EventLoop() {
 				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
 				return
 			}
+			s.stamp(eventCtx, log, event.GameID, resp)
 			err = s.redis.AddClientToChannel(eventCtx, event.GameID, event.UserID, &models.RoomParticipant{
 				ID:       event.UserID,
 				Username: event.Username,
 				IsOwner:  false,
+				Bot:      joinEvent.Bot,
 				Field:    nil,
 			})
 			if err != nil {
@@ -205,6 +231,7 @@ func (s *EventLoop) EventLoop() {
 				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
 				return
 			}
+			s.stamp(eventCtx, log, event.GameID, resp)
 			go func() {
 				var wg sync.WaitGroup
 				if event.IsPublic {
@@ -222,6 +249,7 @@ func (s *EventLoop) EventLoop() {
 				wg.Wait()
 				s.ws.DisconnectRoom(event.GameID, []int{int(event.UserID)})
 			}()
+			go s.checkScoredGame(event.GameID)
 		case models.TypeStartGame:
 			payloadMarshalled, err := json.Marshal(map[string]any{
 				"id": event.GameID,
@@ -244,10 +272,13 @@ func (s *EventLoop) EventLoop() {
 				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
 				return
 			}
+			s.stamp(eventCtx, log, event.GameID, resp)
 			if event.IsPublic {
 				go s.ws.BroadcastEvent(resp)
 			}
 			go s.ws.MulticastEvent(event.GameID, users, resp)
+			go s.cacheSettings(event.GameID)
+			s.bots.Start(event.GameID)
 		case models.TypeClickGame:
 			payloadMarshalled, err := json.Marshal(map[string]any{
 				"id":           event.GameID,
@@ -268,6 +299,42 @@ func (s *EventLoop) EventLoop() {
 				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
 				return
 			}
+			s.stamp(eventCtx, log, event.GameID, resp)
+			go s.ws.MulticastEvent(event.GameID, users, resp)
+		case models.TypeDiffGame:
+			payloadMarshalled, err := json.Marshal(map[string]any{
+				"id":      event.GameID,
+				"user_id": event.UserID,
+				"diffs":   event.Payload,
+			})
+			if err != nil {
+				log.Error("error marshalling event", slog.Any("event", event))
+				continue
+			}
+			resp = &dto_ws.Response{
+				Status:    dto_ws.StatusOK,
+				EventType: dto_ws.ClickGameEventType,
+				Payload:   payloadMarshalled,
+			}
+			users, err := s.redis.GetUsersInChannel(eventCtx, event.GameID)
+			if err != nil {
+				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
+				return
+			}
+			s.stamp(eventCtx, log, event.GameID, resp)
+			go s.ws.MulticastEvent(event.GameID, users, resp)
+		case models.TypeCommitField:
+			resp = &dto_ws.Response{
+				Status:    dto_ws.StatusOK,
+				EventType: dto_ws.CommitFieldEventType,
+				Payload:   event.Payload,
+			}
+			users, err := s.redis.GetUsersInChannel(eventCtx, event.GameID)
+			if err != nil {
+				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
+				return
+			}
+			s.stamp(eventCtx, log, event.GameID, resp)
 			go s.ws.MulticastEvent(event.GameID, users, resp)
 		case models.TypeLoseGame:
 			resp = &dto_ws.Response{
@@ -280,6 +347,7 @@ func (s *EventLoop) EventLoop() {
 				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
 				return
 			}
+			s.stamp(eventCtx, log, event.GameID, resp)
 			go func() {
 				s.ws.MulticastEvent(event.GameID, users, resp)
 				time.Sleep(time.Second) // дисконнектим клиентов в комнате не сразу, а с небольшой задержкой, чтобы успели получить ивент о результате игры
@@ -288,6 +356,11 @@ func (s *EventLoop) EventLoop() {
 				if err != nil {
 					log.Error("error deleting channel", slog.Any("event", event))
 				}
+				err = s.redis.DeleteEvents(eventCtx, event.GameID)
+				if err != nil {
+					log.Error("error deleting room events", slog.Any("event", event))
+				}
+				s.archiveChat(eventCtx, log, event.GameID)
 			}()
 		case models.TypeWinGame:
 			resp = &dto_ws.Response{
@@ -300,6 +373,7 @@ func (s *EventLoop) EventLoop() {
 				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
 				return
 			}
+			s.stamp(eventCtx, log, event.GameID, resp)
 			go func() {
 				s.ws.MulticastEvent(event.GameID, users, resp)
 				time.Sleep(time.Second) // дисконнектим клиентов в комнате не сразу, а с небольшой задержкой, чтобы успели получить ивент о результате игры
@@ -308,6 +382,11 @@ func (s *EventLoop) EventLoop() {
 				if err != nil {
 					log.Error("error deleting channel", slog.Any("event", event))
 				}
+				err = s.redis.DeleteEvents(eventCtx, event.GameID)
+				if err != nil {
+					log.Error("error deleting room events", slog.Any("event", event))
+				}
+				s.archiveChat(eventCtx, log, event.GameID)
 			}()
 		case models.TypeNewMessage:
 			resp = &dto_ws.Response{
@@ -320,14 +399,139 @@ func (s *EventLoop) EventLoop() {
 				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
 				return
 			}
+			s.stamp(eventCtx, log, event.GameID, resp)
+			go s.ws.MulticastEvent(event.GameID, users, resp)
+		case models.TypeEditMessage, models.TypeDeleteMessage:
+			eventType := dto_ws.MessageEditedEventType
+			if event.Type == models.TypeDeleteMessage {
+				eventType = dto_ws.MessageDeletedEventType
+			}
+			resp = &dto_ws.Response{
+				Status:    dto_ws.StatusOK,
+				EventType: eventType,
+				Payload:   event.Payload,
+			}
+			users, err := s.redis.GetUsersInChannel(eventCtx, event.GameID)
+			if err != nil {
+				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
+				continue
+			}
+			s.stamp(eventCtx, log, event.GameID, resp)
+			go s.ws.MulticastEvent(event.GameID, users, resp)
+		case models.TypeEmote:
+			// Эмоции намеренно не получают seq через stamp и не попадают в буфер событий комнаты,
+			// поэтому при переподключении они не переигрываются: устаревшая эмоция только мешает
+			resp = &dto_ws.Response{
+				Status:    dto_ws.StatusOK,
+				EventType: dto_ws.EmoteEventType,
+				Payload:   event.Payload,
+			}
+			users, err := s.redis.GetUsersInChannel(eventCtx, event.GameID)
+			if err != nil {
+				log.Error("error reading channel clients from redis", slog.Any("event", resp), prettylogger.Err(err))
+				continue
+			}
 			go s.ws.MulticastEvent(event.GameID, users, resp)
+		case models.TypeLobbyMessage:
+			resp = &dto_ws.Response{
+				Status:    dto_ws.StatusOK,
+				EventType: dto_ws.LobbyMessageEventType,
+				Payload:   event.Payload,
+			}
+			go s.ws.BroadcastEvent(resp)
+		case models.TypeTournamentRound:
+			resp = &dto_ws.Response{
+				Status:    dto_ws.StatusOK,
+				EventType: dto_ws.TournamentRoundEventType,
+				Payload:   event.Payload,
+			}
+			var round models.TournamentRoundEvent
+			err := json.Unmarshal(event.Payload, &round)
+			if err != nil {
+				log.Error("error unmarshalling event", slog.Any("event", event), prettylogger.Err(err))
+				continue
+			}
+			users := make([]int, 0, 2*len(round.Pairings))
+			for _, pairing := range round.Pairings {
+				for _, id := range pairing.Players {
+					users = append(users, int(id))
+				}
+			}
+			// участники ещё не в комнатах, поэтому уведомление уходит их подключениям в лобби
+			go s.ws.MulticastEvent("", users, resp)
+		case models.TypeDirectMessage, models.TypeInvite:
+			resp = &dto_ws.Response{
+				Status:    dto_ws.StatusOK,
+				EventType: dto_ws.DirectMessageEventType,
+				Payload:   event.Payload,
+			}
+			if event.Type == models.TypeInvite {
+				resp.EventType = dto_ws.InviteEventType
+			}
+			var personal models.PersonalEvent
+			err := json.Unmarshal(event.Payload, &personal)
+			if err != nil {
+				log.Error("error unmarshalling event", slog.Any("event", event), prettylogger.Err(err))
+				continue
+			}
+			go s.ws.MulticastEvent("", []int{int(personal.RecipientID)}, resp)
 		default:
-			log.Warn("unknown event type", slog.String("type", string(event.Type)))
+			log.Warn("unknown event type", slog.Int("type", int(event.Type)))
 			continue
 		}
 	}
 }
 
+// stamp присваивает событию порядковый номер в комнате и сохраняет его в буфер,
+// чтобы клиент после переподключения мог получить пропущенные события
+func (s *EventLoop) stamp(ctx context.Context, log *slog.Logger, roomID string, resp *dto_ws.Response) {
+	seq, err := s.redis.NextSeq(ctx, roomID)
+	if err != nil {
+		log.Error("error getting next event seq", prettylogger.Err(err))
+		return
+	}
+	resp.Seq = seq
+	err = s.redis.SaveEvent(ctx, roomID, resp.Serialize())
+	if err != nil {
+		log.Error("error saving event into buffer", prettylogger.Err(err))
+	}
+}
+
+// cacheSettings сохраняет настройки начатой игры в redis, чтобы ходы не обращались за ними в game-srv.
+// Запускается отдельно, чтобы запрос в game-srv не задерживал обработку событий
+func (s *EventLoop) cacheSettings(roomID string) {
+	_, err := s.play.CacheSettings(context.Background(), roomID)
+	if err != nil {
+		s.log.Error("error caching game settings", slog.String("game_id", roomID), prettylogger.Err(err))
+	}
+}
+
+// checkScoredGame после выхода участника заканчивает игру на очки, если он был последним, кто ещё играл
+func (s *EventLoop) checkScoredGame(roomID string) {
+	err := s.play.CheckScoredGame(context.Background(), roomID)
+	if err != nil {
+		s.log.Error("error checking scored game", slog.String("game_id", roomID), prettylogger.Err(err))
+	}
+}
+
+// archiveChat переносит чат закончившейся игры в game-srv и удаляет его из redis.
+// Если сохранить не удалось, чат остаётся в redis до ttl и попадёт в следующую периодическую архивацию
+func (s *EventLoop) archiveChat(ctx context.Context, log *slog.Logger, roomID string) {
+	exists, err := s.redis.ChatExists(ctx, roomID)
+	if err != nil || !exists {
+		return
+	}
+	err = s.chat.Archive(ctx, roomID)
+	if err != nil {
+		log.Error("error archiving chat", slog.String("game_id", roomID), prettylogger.Err(err))
+		return
+	}
+	err = s.redis.DeleteChat(ctx, roomID)
+	if err != nil {
+		log.Error("error deleting chat", slog.String("game_id", roomID), prettylogger.Err(err))
+	}
+}
+
 func (s *EventLoop) Stop() {
 	s.pubsub.Close()
 }
@@ -5,18 +5,20 @@ import "strconv"
 type CellType string
 
 const (
-	MINE    CellType = "m"
-	EMPTY   CellType = "0"
-	CLOSED  CellType = "c"
-	FLAG    CellType = "f"
-	MINES_1 CellType = "1"
-	MINES_2 CellType = "2"
-	MINES_3 CellType = "3"
-	MINES_4 CellType = "4"
-	MINES_5 CellType = "5"
-	MINES_6 CellType = "6"
-	MINES_7 CellType = "7"
-	MINES_8 CellType = "8"
+	MINE     CellType = "m"
+	EMPTY    CellType = "0"
+	CLOSED   CellType = "c"
+	FLAG     CellType = "f"
+	QUESTION CellType = "q"
+	EXPLODED CellType = "x" // взорванная мина в режиме с жизнями, игра продолжается
+	MINES_1  CellType = "1"
+	MINES_2  CellType = "2"
+	MINES_3  CellType = "3"
+	MINES_4  CellType = "4"
+	MINES_5  CellType = "5"
+	MINES_6  CellType = "6"
+	MINES_7  CellType = "7"
+	MINES_8  CellType = "8"
 )
 
 type Cell struct {
@@ -26,6 +28,13 @@ type Cell struct {
 	HasMine       *bool    `json:"has_mine,omitempty"`
 }
 
+// CellChange описывает изменение видимого состояния клетки после хода
+type CellChange struct {
+	Row   int      `json:"row"`
+	Col   int      `json:"col"`
+	Value CellType `json:"value"`
+}
+
 func NewCell(value CellType) *Cell {
 	hasMine := false
 	return &Cell{
@@ -48,6 +57,11 @@ func (c *Cell) SetMine() {
 	c.HasMine = &hasMine
 }
 
+// isOpenMine проверяет по видимому значению, что клетка - открытая или взорванная мина
+func (c *Cell) isOpenMine() bool {
+	return c.IsOpen && (c.Value == MINE || c.Value == EXPLODED)
+}
+
 func (c *Cell) SetOpenValue() {
 	if !c.IsOpen {
 		return
@@ -0,0 +1,197 @@
+package game
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+)
+
+// Бинарный формат поля (версия 1):
+//
+//	[0]     версия формата
+//	[1:3]   rows, [3:5] cols, [5:7] mines, [7:9] cells_open (uint16, big endian)
+//	[9]     флаги поля (бит 0 - mine_is_open, бит 1 - знаки вопроса включены)
+//	[10:18] сид генерации (int64), [18:20] origin row, [20:22] origin col (uint16)
+//	[22]    топология поля (индекс в topologies)
+//	[23]    оставшиеся жизни
+//	далее   количество соседних мин, по полубайту на клетку
+//	далее   битовые маски мин, открытых клеток, флагов, знаков вопроса и взорванных мин, по биту на клетку
+//
+// Клетки перечисляются построчно. Количество флагов и взорванных мин не хранится, а считается по маскам.
+const (
+	codecVersion    = 1
+	codecHeaderSize = 24
+	codecBitsets    = 5
+
+	fieldFlagMineIsOpen = 1 << 0
+	fieldFlagQuestions  = 1 << 1
+)
+
+var (
+	ErrInvalidEncoding = errors.New("invalid field encoding")
+)
+
+// Encode упаковывает поле в компактный бинарный формат
+func (f *Field) Encode() []byte {
+	cells := f.Rows * f.Cols
+	nibblesSize := (cells + 1) / 2
+	bitsetSize := (cells + 7) / 8
+
+	data := make([]byte, codecHeaderSize+nibblesSize+codecBitsets*bitsetSize)
+	data[0] = codecVersion
+	binary.BigEndian.PutUint16(data[1:], uint16(f.Rows))
+	binary.BigEndian.PutUint16(data[3:], uint16(f.Cols))
+	binary.BigEndian.PutUint16(data[5:], uint16(f.Mines))
+	binary.BigEndian.PutUint16(data[7:], uint16(f.CellsOpen))
+	if f.MineIsOpen {
+		data[9] |= fieldFlagMineIsOpen
+	}
+	if f.Questions {
+		data[9] |= fieldFlagQuestions
+	}
+	binary.BigEndian.PutUint64(data[10:], uint64(f.seed))
+	binary.BigEndian.PutUint16(data[18:], uint16(f.origin.Row))
+	binary.BigEndian.PutUint16(data[20:], uint16(f.origin.Col))
+	data[22] = byte(topologyID(f.Topology))
+	data[23] = byte(f.Lives)
+
+	nibbles := data[codecHeaderSize : codecHeaderSize+nibblesSize]
+	mines := data[codecHeaderSize+nibblesSize : codecHeaderSize+nibblesSize+bitsetSize]
+	open := data[codecHeaderSize+nibblesSize+bitsetSize : codecHeaderSize+nibblesSize+2*bitsetSize]
+	flags := data[codecHeaderSize+nibblesSize+2*bitsetSize : codecHeaderSize+nibblesSize+3*bitsetSize]
+	questions := data[codecHeaderSize+nibblesSize+3*bitsetSize : codecHeaderSize+nibblesSize+4*bitsetSize]
+	exploded := data[codecHeaderSize+nibblesSize+4*bitsetSize:]
+
+	for row := 0; row < f.Rows; row++ {
+		for col := 0; col < f.Cols; col++ {
+			i := row*f.Cols + col
+			cell := f.Grid[row][col]
+
+			nibbles[i/2] |= byte(cell.NeighborMines&0x0f) << (4 * (i % 2))
+			if cell.IsMine() {
+				mines[i/8] |= 1 << (i % 8)
+			}
+			if cell.IsOpen {
+				open[i/8] |= 1 << (i % 8)
+			}
+			if cell.Value == FLAG {
+				flags[i/8] |= 1 << (i % 8)
+			}
+			if cell.Value == QUESTION {
+				questions[i/8] |= 1 << (i % 8)
+			}
+			if cell.Value == EXPLODED {
+				exploded[i/8] |= 1 << (i % 8)
+			}
+		}
+	}
+
+	return data
+}
+
+// DecodeField восстанавливает поле из бинарного формата Encode
+func DecodeField(data []byte) (*Field, error) {
+	if len(data) == 0 {
+		return nil, ErrInvalidEncoding
+	}
+	if data[0] != codecVersion || len(data) < codecHeaderSize {
+		return nil, ErrInvalidEncoding
+	}
+
+	f := &Field{
+		Rows:       int(binary.BigEndian.Uint16(data[1:])),
+		Cols:       int(binary.BigEndian.Uint16(data[3:])),
+		Mines:      int(binary.BigEndian.Uint16(data[5:])),
+		CellsOpen:  int(binary.BigEndian.Uint16(data[7:])),
+		MineIsOpen: data[9]&fieldFlagMineIsOpen != 0,
+		Questions:  data[9]&fieldFlagQuestions != 0,
+		Lives:      int(data[23]),
+	}
+	f.seed = int64(binary.BigEndian.Uint64(data[10:]))
+	f.origin = Point{
+		Row: int(binary.BigEndian.Uint16(data[18:])),
+		Col: int(binary.BigEndian.Uint16(data[20:])),
+	}
+
+	if int(data[22]) >= len(topologies) {
+		return nil, ErrInvalidEncoding
+	}
+	topology := topologies[data[22]]
+	if topology != Square {
+		f.Topology = topology.Name()
+	}
+
+	cells := f.Rows * f.Cols
+	nibblesSize := (cells + 1) / 2
+	bitsetSize := (cells + 7) / 8
+	if len(data) != codecHeaderSize+nibblesSize+codecBitsets*bitsetSize {
+		return nil, ErrInvalidEncoding
+	}
+
+	nibbles := data[codecHeaderSize : codecHeaderSize+nibblesSize]
+	mines := data[codecHeaderSize+nibblesSize : codecHeaderSize+nibblesSize+bitsetSize]
+	open := data[codecHeaderSize+nibblesSize+bitsetSize : codecHeaderSize+nibblesSize+2*bitsetSize]
+	flags := data[codecHeaderSize+nibblesSize+2*bitsetSize : codecHeaderSize+nibblesSize+3*bitsetSize]
+	questions := data[codecHeaderSize+nibblesSize+3*bitsetSize : codecHeaderSize+nibblesSize+4*bitsetSize]
+	exploded := data[codecHeaderSize+nibblesSize+4*bitsetSize:]
+
+	f.Grid = make([][]*Cell, f.Rows)
+	for row := 0; row < f.Rows; row++ {
+		f.Grid[row] = make([]*Cell, f.Cols)
+		for col := 0; col < f.Cols; col++ {
+			i := row*f.Cols + col
+
+			cell := NewCell(CLOSED)
+			cell.NeighborMines = int(nibbles[i/2]>>(4*(i%2))) & 0x0f
+			if cell.NeighborMines > topology.MaxNeighbors() {
+				return nil, ErrInvalidEncoding
+			}
+			if mines[i/8]&(1<<(i%8)) != 0 {
+				cell.SetMine()
+			}
+			if open[i/8]&(1<<(i%8)) != 0 {
+				cell.IsOpen = true
+				cell.SetOpenValue()
+				if exploded[i/8]&(1<<(i%8)) != 0 {
+					cell.Value = EXPLODED
+					f.Exploded++
+				}
+			} else if flags[i/8]&(1<<(i%8)) != 0 {
+				cell.Value = FLAG
+				f.Flags++
+			} else if questions[i/8]&(1<<(i%8)) != 0 {
+				cell.Value = QUESTION
+			}
+			f.Grid[row][col] = cell
+		}
+	}
+
+	return f, nil
+}
+
+// DecodeJSONField восстанавливает поле из json, в котором поля участников хранились до бинарного формата.
+// Нужен, чтобы игры, начатые до обновления, не теряли участников
+func DecodeJSONField(data []byte) (*Field, error) {
+	var f Field
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if len(f.Grid) != f.Rows {
+		return nil, ErrInvalidEncoding
+	}
+	f.Flags = 0 // в старом формате счётчика флагов нет, он считается по клеткам
+	for _, row := range f.Grid {
+		if len(row) != f.Cols {
+			return nil, ErrInvalidEncoding
+		}
+		for _, cell := range row {
+			if cell == nil {
+				return nil, ErrInvalidEncoding
+			}
+			if cell.Value == FLAG {
+				f.Flags++
+			}
+		}
+	}
+	return &f, nil
+}
@@ -0,0 +1,199 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldCodec(t *testing.T) {
+	testCases := []struct {
+		name  string
+		field func() *Field
+	}{
+		{
+			name:  "closed field",
+			field: CreateClosedField,
+		},
+		{
+			name: "opened field",
+			field: func() *Field {
+				f := readGrid("testcases/test_001.json")
+				f.OpenCell(0, 0)
+				f.SetFlag(4, 3)
+				return f
+			},
+		},
+		{
+			name: "generated field",
+			field: func() *Field {
+				f := CreateField(42, Square, 3, 5)
+				f.OpenCell(3, 5)
+				return f
+			},
+		},
+		{
+			name: "hex field",
+			field: func() *Field {
+				f := CreateField(42, Hex, 3, 5)
+				f.OpenCell(3, 5)
+				return f
+			},
+		},
+		{
+			name: "torus field",
+			field: func() *Field {
+				f := CreateField(42, Torus, 0, 0)
+				f.OpenCell(0, 0)
+				return f
+			},
+		},
+		{
+			name: "marked field",
+			field: func() *Field {
+				f := CreateField(42, Square, 3, 5)
+				f.Questions = true
+				f.OpenCell(3, 5)
+				f.SetFlag(0, 0)
+				f.SetFlag(7, 7)
+				f.SetFlag(7, 7)
+				return f
+			},
+		},
+		{
+			name: "exploded field",
+			field: func() *Field {
+				f := readGrid("testcases/test_001.json")
+				f.Lives = 3
+				f.OpenCell(0, 0)
+				f.OpenCell(4, 2)
+				return f
+			},
+		},
+		{
+			name: "lost field",
+			field: func() *Field {
+				f := readGrid("testcases/test_001.json")
+				f.OpenCell(4, 2)
+				return f
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			field := tc.field()
+
+			decoded, err := DecodeField(field.Encode())
+			require.NoError(t, err)
+			require.Equal(t, field.Rows, decoded.Rows)
+			require.Equal(t, field.Cols, decoded.Cols)
+			require.Equal(t, field.Mines, decoded.Mines)
+			require.Equal(t, field.CellsOpen, decoded.CellsOpen)
+			require.Equal(t, field.MineIsOpen, decoded.MineIsOpen)
+			require.Equal(t, field.Topology, decoded.Topology)
+			require.Equal(t, field.Flags, decoded.Flags)
+			require.Equal(t, field.Questions, decoded.Questions)
+			require.Equal(t, field.Lives, decoded.Lives)
+			require.Equal(t, field.Exploded, decoded.Exploded)
+			for row := 0; row < field.Rows; row++ {
+				for col := 0; col < field.Cols; col++ {
+					require.Equal(t, field.Grid[row][col].Value, decoded.Grid[row][col].Value)
+					require.Equal(t, field.Grid[row][col].IsOpen, decoded.Grid[row][col].IsOpen)
+					require.Equal(t, field.Grid[row][col].IsMine(), decoded.Grid[row][col].IsMine())
+					require.Equal(t, field.Grid[row][col].NeighborMines, decoded.Grid[row][col].NeighborMines)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeFieldInvalid(t *testing.T) {
+	data := CreateField(1, Square, 0, 0).Encode()
+
+	_, err := DecodeField(nil)
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+	_, err = DecodeField(data[:len(data)-1])
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	data[0] = codecVersion + 1
+	_, err = DecodeField(data)
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	data = CreateField(1, Square, 0, 0).Encode()
+	data[22] = byte(len(topologies))
+	_, err = DecodeField(data)
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+
+	// На шестиугольном поле у клетки не может быть больше 6 соседних мин
+	data = CreateField(1, Hex, 0, 0).Encode()
+	data[codecHeaderSize] = 7
+	_, err = DecodeField(data)
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+}
+
+func TestDecodeJSONField(t *testing.T) {
+	field := readGrid("testcases/test_001.json")
+	field.OpenCell(0, 0)
+	field.SetFlag(4, 3)
+	data, err := json.Marshal(field)
+	require.NoError(t, err)
+
+	decoded, err := DecodeJSONField(data)
+	require.NoError(t, err)
+	require.Equal(t, field.CellsOpen, decoded.CellsOpen)
+	require.Equal(t, 1, decoded.Flags)
+	require.Equal(t, field.Layout(), decoded.Layout())
+
+	_, err = DecodeJSONField([]byte(`{"rows":2,"cols":2,"grid":[]}`))
+	require.ErrorIs(t, err, ErrInvalidEncoding)
+}
+
+func benchmarkField() *Field {
+	f := CreateField(1, Square, 4, 4)
+	f.OpenCell(4, 4)
+	return f
+}
+
+func BenchmarkFieldEncode(b *testing.B) {
+	f := benchmarkField()
+	b.ReportAllocs()
+	for b.Loop() {
+		f.Encode()
+	}
+	b.ReportMetric(float64(len(f.Encode())), "bytes/field")
+}
+
+func BenchmarkFieldDecode(b *testing.B) {
+	data := benchmarkField().Encode()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := DecodeField(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFieldJSONMarshal(b *testing.B) {
+	f := benchmarkField()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := json.Marshal(f); err != nil {
+			b.Fatal(err)
+		}
+	}
+	data, _ := json.Marshal(f)
+	b.ReportMetric(float64(len(data)), "bytes/field")
+}
+
+func BenchmarkFieldJSONUnmarshal(b *testing.B) {
+	data, _ := json.Marshal(benchmarkField())
+	b.ReportAllocs()
+	for b.Loop() {
+		var f Field
+		if err := json.Unmarshal(data, &f); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Reveal раскрытие поля после окончания игры, по которому клиент может проверить ранее опубликованную commitment
+type Reveal struct {
+	Seed       int64  `json:"seed,string"` // строкой, так как int64 не помещается в number без потери точности
+	Origin     Point  `json:"origin"`
+	Layout     string `json:"layout"`
+	Commitment string `json:"commitment"`
+	Topology   string `json:"topology,omitempty"` // топология поля, нужна чтобы заново сгенерировать расположение мин
+}
+
+// Layout возвращает расположение мин построчно: '*' - мина, '.' - пустая клетка, строки разделены '/'
+func (f *Field) Layout() string {
+	var sb strings.Builder
+	for row := 0; row < f.Rows; row++ {
+		if row > 0 {
+			sb.WriteByte('/')
+		}
+		for col := 0; col < f.Cols; col++ {
+			if f.Grid[row][col].IsMine() {
+				sb.WriteByte('*')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Commitment возвращает sha256 от сида, origin и расположения мин в hex.
+// Публикуется при генерации поля, сам сид раскрывается только в конце игры
+func (f *Field) Commitment() string {
+	return commitment(f.seed, f.origin, f.Layout())
+}
+
+// Reveal возвращает данные для проверки commitment поля
+func (f *Field) Reveal() *Reveal {
+	layout := f.Layout()
+	return &Reveal{
+		Seed:       f.seed,
+		Origin:     f.origin,
+		Layout:     layout,
+		Commitment: commitment(f.seed, f.origin, layout),
+		Topology:   f.Topology,
+	}
+}
+
+// Verify проверяет, что раскрытие соответствует commitment и что расположение мин действительно получается из сида
+func (r *Reveal) Verify() bool {
+	if commitment(r.Seed, r.Origin, r.Layout) != r.Commitment {
+		return false
+	}
+	topology, err := ParseTopology(r.Topology)
+	if err != nil {
+		return false
+	}
+	return CreateField(r.Seed, topology, r.Origin.Row, r.Origin.Col).Layout() == r.Layout
+}
+
+// commitment хеширует строку вида "<seed>|<row>,<col>|<layout>"
+func commitment(seed int64, origin Point, layout string) string {
+	hash := sha256.Sum256(fmt.Appendf(nil, "%d|%d,%d|%s", seed, origin.Row, origin.Col, layout))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReveal(t *testing.T) {
+	field := CreateField(7, Square, 2, 6)
+	committed := field.Commitment()
+
+	testCases := []struct {
+		name   string
+		modify func(r *Reveal)
+		valid  bool
+	}{
+		{
+			name:   "honest reveal",
+			modify: func(r *Reveal) {},
+			valid:  true,
+		},
+		{
+			name: "moved mine",
+			modify: func(r *Reveal) {
+				r.Layout = strings.Replace(r.Layout, "*.", ".*", 1)
+			},
+			valid: false,
+		},
+		{
+			name: "other seed",
+			modify: func(r *Reveal) {
+				r.Seed++
+			},
+			valid: false,
+		},
+		{
+			name: "layout not from seed",
+			modify: func(r *Reveal) {
+				r.Layout = strings.Replace(r.Layout, "*.", ".*", 1)
+				r.Commitment = commitment(r.Seed, r.Origin, r.Layout)
+			},
+			valid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reveal := field.Reveal()
+			require.Equal(t, committed, reveal.Commitment)
+			tc.modify(reveal)
+			require.Equal(t, tc.valid, reveal.Verify())
+		})
+	}
+}
+
+func TestCommitmentSurvivesPlay(t *testing.T) {
+	field := CreateField(7, Square, 2, 6)
+	committed := field.Commitment()
+
+	field.OpenCell(2, 6)
+	field.SetFlag(0, 0)
+	decoded, err := DecodeField(field.Encode())
+	require.NoError(t, err)
+	require.Equal(t, committed, decoded.Commitment())
+	require.True(t, decoded.Reveal().Verify())
+}
@@ -3,15 +3,21 @@ package game
 import (
 	"errors"
 	"math/rand"
+	"time"
 )
 
 const fieldSize = 8
 const mineCount = 10
 
+// seededNoGuessAttempts сколько полей перебирается при генерации по сиду в режиме без угадывания
+const seededNoGuessAttempts = 1000
+
 var (
 	ErrAlreadyOpen    = errors.New("Клетка уже открыта")
 	ErrFlagOnOpenCell = errors.New("Нельзя поставить флаг на открытую клетку")
 	ErrFieldSize      = errors.New("Выход за пределы поля")
+	ErrChordClosed    = errors.New("Открыть клетки вокруг можно только у открытой клетки")
+	ErrChordFlags     = errors.New("Количество флагов вокруг клетки не совпадает с её числом")
 )
 
 type Field struct {
@@ -21,6 +27,15 @@ type Field struct {
 	CellsOpen  int       `json:"cells_open"`
 	MineIsOpen bool      `json:"mine_is_open"`
 	Grid       [][]*Cell `json:"grid"`
+	Topology   string    `json:"topology,omitempty"`  // имя топологии, пусто для обычного поля
+	Flags      int       `json:"flags"`               // количество поставленных флагов, для счётчика оставшихся мин
+	Questions  bool      `json:"questions,omitempty"` // после флага ставится знак вопроса
+	Lives      int       `json:"lives,omitempty"`     // оставшиеся жизни в режиме с жизнями, 0 в обычной игре
+	Exploded   int       `json:"exploded,omitempty"`  // количество взорванных мин, на которых потрачены жизни
+
+	changes []*CellChange // клетки, изменённые с последнего вызова TakeChanges
+	seed    int64         // сид, из которого сгенерировано расположение мин
+	origin  Point         // клетка, вокруг которой при генерации не ставятся мины
 }
 
 func NewField() *Field {
@@ -33,20 +48,18 @@ func NewField() *Field {
 	}
 }
 
+// OpenCell открывает клетку. Повторное открытие открытой клетки ничего не делает, клетки вокруг открывает Chord
 func (f *Field) OpenCell(row, col int) error {
-	if row < 0 || row >= f.Rows || col < 0 || col >= f.Cols {
+	if !f.contains(row, col) {
 		return ErrFieldSize
 	}
 
 	if f.Grid[row][col].IsOpen {
-		f.openCellsAround(row, col)
 		return nil
 	}
 
 	if f.Grid[row][col].IsMine() {
-		f.Grid[row][col].IsOpen = true
-		f.MineIsOpen = true
-		f.Grid[row][col].SetOpenValue()
+		f.openMine(row, col)
 		return nil
 	}
 
@@ -54,70 +67,121 @@ func (f *Field) OpenCell(row, col int) error {
 	return nil
 }
 
-func (f *Field) openCellsAround(row, col int) {
-	for i := -1; i <= 1; i++ {
-		for j := -1; j <= 1; j++ {
-			if i == 0 && j == 0 {
-				continue
-			}
-			cellRow := row + i
-			cellCol := col + j
-			if cellRow < 0 || cellRow >= f.Rows || cellCol < 0 || cellCol >= f.Cols {
-				continue
-			}
+// Chord открывает закрытые клетки без флагов вокруг открытой клетки, если флагов вокруг столько же, сколько мин.
+// Если флаги стоят неверно, открывается мина и игра проиграна
+func (f *Field) Chord(row, col int) error {
+	if !f.contains(row, col) {
+		return ErrFieldSize
+	}
+	if !f.Grid[row][col].IsOpen {
+		return ErrChordClosed
+	}
+	if f.flagsAround(row, col) != f.Grid[row][col].NeighborMines {
+		return ErrChordFlags
+	}
+	f.openCellsAround(row, col)
+	return nil
+}
+
+// flagsAround подсчитывает флаги вокруг клетки
+func (f *Field) flagsAround(row, col int) int {
+	c := 0
+	for _, p := range f.neighbors(row, col) {
+		// Взорванная мина тоже известна, поэтому считается как флаг
+		if cell := f.Grid[p.Row][p.Col]; cell.Value == FLAG || cell.Value == EXPLODED {
+			c++
+		}
+	}
+	return c
+}
 
-			cell := f.Grid[cellRow][cellCol]
-			if cell.IsOpen == false && cell.Value == CLOSED {
-				if cell.IsMine() {
-					cell.IsOpen = true
-					f.MineIsOpen = true
-					cell.SetOpenValue()
-				} else {
-					f.openNeighborCells(cellRow, cellCol)
-				}
+// openCellsAround открывает закрытые клетки вокруг открытой и возвращает открытые клетки
+func (f *Field) openCellsAround(row, col int) []Point {
+	opened := make([]Point, 0)
+	for _, p := range f.neighbors(row, col) {
+		cell := f.Grid[p.Row][p.Col]
+		if cell.IsOpen == false && cell.Value != FLAG {
+			if cell.IsMine() {
+				f.openMine(p.Row, p.Col)
+				opened = append(opened, p)
+			} else {
+				opened = append(opened, f.openNeighborCells(p.Row, p.Col)...)
 			}
 		}
 	}
+	return opened
 }
 
+// IsWin проверяет, что открыты все клетки без мин. Взорванные мины в режиме с жизнями победе не мешают
 func (f *Field) IsWin() bool {
 	totalCells := f.Rows * f.Cols
 	return f.CellsOpen == totalCells-f.Mines && !f.MineIsOpen
 }
 
-// openNeighborCells рекурсивно открывает соседние клетки
-func (f *Field) openNeighborCells(row, col int) {
-	if row < 0 || col < 0 || row >= f.Rows || col >= f.Cols {
-		return
+// openNeighborCells открывает клетку и, если вокруг неё нет мин, всю область без мин обходом в ширину.
+// Возвращает открытые клетки в порядке открытия. Мины никогда не открываются
+func (f *Field) openNeighborCells(row, col int) []Point {
+	if !f.contains(row, col) {
+		return nil
+	}
+	if cell := f.Grid[row][col]; cell.IsOpen || cell.IsMine() {
+		return nil
 	}
 
-	cell := f.Grid[row][col]
-
-	if cell.IsOpen || cell.IsMine() {
-		return
+	f.openSafeCell(row, col)
+	opened := []Point{{row, col}}
+	// opened одновременно очередь обхода: клетки открываются при постановке в очередь, поэтому не посещаются повторно
+	for i := 0; i < len(opened); i++ {
+		p := opened[i]
+		if f.Grid[p.Row][p.Col].NeighborMines > 0 {
+			continue
+		}
+		for _, n := range f.neighbors(p.Row, p.Col) {
+			if cell := f.Grid[n.Row][n.Col]; cell.IsOpen || cell.IsMine() {
+				continue
+			}
+			f.openSafeCell(n.Row, n.Col)
+			opened = append(opened, n)
+		}
 	}
+	return opened
+}
 
+// openSafeCell открывает клетку без мины. Флаг на открываемой клетке снимается
+func (f *Field) openSafeCell(row, col int) {
+	cell := f.Grid[row][col]
+	if cell.Value == FLAG {
+		f.Flags--
+	}
 	cell.IsOpen = true
 	cell.SetOpenValue()
 	f.CellsOpen++
+	f.trackChange(row, col)
+}
 
-	if cell.NeighborMines > 0 {
-		return
+// openMine открывает клетку с миной. Если у участника есть запасные жизни, мина взрывается и тратит одну из них,
+// иначе игра участника проиграна
+func (f *Field) openMine(row, col int) {
+	cell := f.Grid[row][col]
+	if cell.Value == FLAG {
+		f.Flags--
 	}
-
-	for i := -1; i <= 1; i++ {
-		for j := -1; j <= 1; j++ {
-			if i == 0 && j == 0 {
-				continue
-			}
-
-			f.openNeighborCells(row+i, col+j)
-		}
+	cell.IsOpen = true
+	cell.SetOpenValue()
+	if f.Lives > 1 {
+		f.Lives--
+		f.Exploded++
+		cell.Value = EXPLODED
+	} else {
+		f.Lives = 0
+		f.MineIsOpen = true
 	}
+	f.trackChange(row, col)
 }
 
+// SetFlag переключает метку на закрытой клетке
 func (f *Field) SetFlag(row int, col int) error {
-	if row < 0 || row >= f.Rows || col < 0 || col >= f.Cols {
+	if !f.contains(row, col) {
 		return ErrFieldSize
 	}
 
@@ -125,14 +189,92 @@ func (f *Field) SetFlag(row int, col int) error {
 	if cell.IsOpen {
 		return ErrFlagOnOpenCell
 	}
-	if cell.Value == FLAG {
+	// Метки меняются по кругу: закрыта -> флаг -> вопрос (если включены) -> закрыта
+	switch cell.Value {
+	case FLAG:
+		f.Flags--
+		if f.Questions {
+			cell.Value = QUESTION
+		} else {
+			cell.Value = CLOSED
+		}
+	case QUESTION:
 		cell.Value = CLOSED
-	} else {
+	default:
+		f.Flags++
 		cell.Value = FLAG
 	}
+	f.trackChange(row, col)
 	return nil
 }
 
+// TakeChanges возвращает клетки, изменённые с прошлого вызова, и очищает список
+func (f *Field) TakeChanges() []*CellChange {
+	changes := f.changes
+	f.changes = nil
+	if changes == nil {
+		changes = make([]*CellChange, 0)
+	}
+	return changes
+}
+
+func (f *Field) trackChange(row, col int) {
+	f.changes = append(f.changes, &CellChange{Row: row, Col: col, Value: f.Grid[row][col].Value})
+}
+
+// contains проверяет, что клетка (row, col) лежит на поле
+func (f *Field) contains(row, col int) bool {
+	return row >= 0 && row < f.Rows && col >= 0 && col < f.Cols
+}
+
+// topology возвращает топологию поля, неизвестное имя считается обычным полем
+func (f *Field) topology() Topology {
+	t, err := ParseTopology(f.Topology)
+	if err != nil {
+		return Square
+	}
+	return t
+}
+
+// neighbors возвращает координаты соседних клеток в топологии поля
+func (f *Field) neighbors(row, col int) []Point {
+	return f.topology().Neighbors(f.Rows, f.Cols, row, col)
+}
+
+// clone создаёт независимую копию поля
+func (f *Field) clone() *Field {
+	c := *f
+	c.changes = nil
+	c.Grid = make([][]*Cell, f.Rows)
+	for row := range f.Grid {
+		c.Grid[row] = make([]*Cell, f.Cols)
+		for col, cell := range f.Grid[row] {
+			cellCopy := *cell
+			hasMine := cell.IsMine()
+			cellCopy.HasMine = &hasMine
+			c.Grid[row][col] = &cellCopy
+		}
+	}
+	return &c
+}
+
+// Masked возвращает копию поля для отправки клиенту, в которой скрыто расположение мин. Исходное поле не меняется
+func (f *Field) Masked() *Field {
+	c := *f
+	c.changes = nil
+	c.Grid = make([][]*Cell, len(f.Grid))
+	for row := range f.Grid {
+		c.Grid[row] = make([]*Cell, len(f.Grid[row]))
+		for col, cell := range f.Grid[row] {
+			cellCopy := *cell
+			cellCopy.NeighborMines = 0
+			cellCopy.HasMine = nil
+			c.Grid[row][col] = &cellCopy
+		}
+	}
+	return &c
+}
+
 // CalculateFieldNeighborMines подсчитывает количество соседних мин в каждой клетке
 func (f *Field) calculateFieldNeighborMines() {
 	for row := 0; row < f.Rows; row++ {
@@ -145,27 +287,35 @@ func (f *Field) calculateFieldNeighborMines() {
 // calculateNeighborMines подсчитывает количество соседних мин в одной клетке
 func (f *Field) calculateNeighborMines(row, col int) int {
 	c := 0
-	for i := -1; i <= 1; i++ {
-		for j := -1; j <= 1; j++ {
-			if row+i < 0 || col+j < 0 || row+i >= fieldSize || col+j >= fieldSize || (i == 0 && j == 0) {
-				continue
-			}
-			if f.Grid[row+i][col+j].IsMine() {
-				c++
-			}
+	for _, p := range f.neighbors(row, col) {
+		if f.Grid[p.Row][p.Col].IsMine() {
+			c++
 		}
 	}
 	return c
 }
 
-// CreateGrid создаёт игровое поле
+// NewRand создаёт генератор случайных чисел для генерации поля
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// CreateGrid создаёт игровое поле из сида в заданной топологии
 // firstRow, firstCol необходимы для того, чтобы генерировать поле после первого нажатия
-func CreateField(firstRow, firstCol int) *Field {
+func CreateField(seed int64, topology Topology, firstRow, firstCol int) *Field {
+	rng := NewRand(seed)
 	f := CreateClosedField()
+	f.seed = seed
+	f.origin = Point{Row: firstRow, Col: firstCol}
+	if topology != Square {
+		f.Topology = topology.Name()
+	}
+	// Первая клетка и её соседи остаются без мин, чтобы первый ход открывал область
+	safe := append(f.neighbors(firstRow, firstCol), f.origin)
 	for i := 0; i < f.Mines; i++ {
-		x, y := rand.Intn(f.Rows), rand.Intn(f.Cols)
-		for f.Grid[x][y].Value == MINE || (firstRow-1 <= x && x <= firstRow+1 && firstCol-1 <= y && y <= firstCol+1) {
-			x, y = rand.Intn(f.Rows), rand.Intn(f.Cols)
+		x, y := rng.Intn(f.Rows), rng.Intn(f.Cols)
+		for f.Grid[x][y].IsMine() || containsPoint(safe, Point{x, y}) {
+			x, y = rng.Intn(f.Rows), rng.Intn(f.Cols)
 		}
 		f.Grid[x][y].SetMine()
 	}
@@ -173,6 +323,40 @@ func CreateField(firstRow, firstCol int) *Field {
 	return f
 }
 
+// CreateNoGuessField создаёт поле, которое можно пройти без угадывания, начиная с клетки (firstRow, firstCol).
+// Сид каждой попытки берётся из rng. Если за timeout такое поле не найдено, возвращается последнее сгенерированное и false
+func CreateNoGuessField(rng *rand.Rand, topology Topology, firstRow, firstCol int, timeout time.Duration) (*Field, bool) {
+	deadline := time.Now().Add(timeout)
+	for {
+		f := CreateField(rng.Int63(), topology, firstRow, firstCol)
+		if IsSolvable(f, firstRow, firstCol) {
+			return f, true
+		}
+		if time.Now().After(deadline) {
+			return f, false
+		}
+	}
+}
+
+// CreateSeededField создаёт поле по сиду игры, одинаковое для всех участников.
+// Стартовая клетка выбирается сервером по тому же сиду и открывается сразу, её окрестность гарантированно без мин.
+// Для noGuess число попыток ограничено seededNoGuessAttempts, а не временем, чтобы результат зависел только от сида
+func CreateSeededField(seed int64, topology Topology, noGuess bool) (*Field, Point) {
+	rng := NewRand(seed)
+	opening := Point{Row: rng.Intn(fieldSize), Col: rng.Intn(fieldSize)}
+
+	var f *Field
+	for attempt := 0; attempt < seededNoGuessAttempts; attempt++ {
+		f = CreateField(rng.Int63(), topology, opening.Row, opening.Col)
+		if !noGuess || IsSolvable(f, opening.Row, opening.Col) {
+			break
+		}
+	}
+	f.OpenCell(opening.Row, opening.Col)
+	f.TakeChanges()
+	return f, opening
+}
+
 // CreateClosedField создаёт закрытое игровое поле без мин
 func CreateClosedField() *Field {
 	f := NewField()
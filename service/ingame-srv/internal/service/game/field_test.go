@@ -3,6 +3,7 @@ package game
 import (
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"os"
 	"testing"
 
@@ -46,7 +47,7 @@ func TestField(t *testing.T) {
 }
 
 func TestField2(t *testing.T) {
-	field := CreateField(4, 4)
+	field := CreateField(1, Square, 4, 4)
 	for row := 0; row < fieldSize; row++ {
 		for col := 0; col < fieldSize; col++ {
 			if field.Grid[row][col].IsOpen {
@@ -63,6 +64,42 @@ func TestField2(t *testing.T) {
 	}
 }
 
+func TestFieldChanges(t *testing.T) {
+	field := readGrid("testcases/test_001.json")
+
+	field.OpenCell(0, 0)
+	changes := field.TakeChanges()
+	require.Len(t, changes, field.CellsOpen)
+	for _, change := range changes {
+		require.True(t, field.Grid[change.Row][change.Col].IsOpen)
+		require.Equal(t, field.Grid[change.Row][change.Col].Value, change.Value)
+	}
+	require.Empty(t, field.TakeChanges())
+
+	require.NoError(t, field.SetFlag(4, 3))
+	require.Equal(t, []*CellChange{{Row: 4, Col: 3, Value: FLAG}}, field.TakeChanges())
+}
+
+func TestMasked(t *testing.T) {
+	field, _ := CreateSeededField(7, Square, false)
+	layout := field.Layout()
+	reveal := field.Reveal()
+
+	masked := field.Masked()
+	for row := range masked.Grid {
+		for col, cell := range masked.Grid[row] {
+			require.Nil(t, cell.HasMine)
+			require.Zero(t, cell.NeighborMines)
+			require.Equal(t, field.Grid[row][col].Value, cell.Value)
+			require.Equal(t, field.Grid[row][col].IsOpen, cell.IsOpen)
+		}
+	}
+
+	require.Equal(t, layout, field.Layout())
+	require.Equal(t, reveal, field.Reveal())
+	require.True(t, field.Reveal().Verify())
+}
+
 func readGrid(file string) *Field {
 	data, err := os.ReadFile(file)
 	if err != nil {
@@ -75,3 +112,315 @@ func readGrid(file string) *Field {
 	}
 	return &f
 }
+
+func TestCreateSeededField(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		first, opening := CreateSeededField(seed, Square, seed%2 == 0)
+		second, secondOpening := CreateSeededField(seed, Square, seed%2 == 0)
+		require.Equal(t, opening, secondOpening)
+		require.True(t, first.Grid[opening.Row][opening.Col].IsOpen)
+		require.Equal(t, 0, first.Grid[opening.Row][opening.Col].NeighborMines)
+
+		mines := 0
+		for row := 0; row < fieldSize; row++ {
+			for col := 0; col < fieldSize; col++ {
+				require.Equal(t, first.Grid[row][col].IsMine(), second.Grid[row][col].IsMine())
+				require.Equal(t, first.Grid[row][col].IsOpen, second.Grid[row][col].IsOpen)
+				if first.Grid[row][col].IsMine() {
+					mines++
+				}
+			}
+		}
+		require.Equal(t, mineCount, mines)
+	}
+}
+
+// randomField создаёт закрытое поле произвольного размера со случайно расставленными минами
+func randomField(rng *rand.Rand, topology Topology, rows, cols, mines int) *Field {
+	f := &Field{Rows: rows, Cols: cols, Mines: mines}
+	if topology != Square {
+		f.Topology = topology.Name()
+	}
+	f.Grid = make([][]*Cell, rows)
+	for row := range f.Grid {
+		f.Grid[row] = make([]*Cell, cols)
+		for col := range f.Grid[row] {
+			f.Grid[row][col] = NewCell(CLOSED)
+		}
+	}
+	for placed := 0; placed < mines; {
+		row, col := rng.Intn(rows), rng.Intn(cols)
+		if f.Grid[row][col].IsMine() {
+			continue
+		}
+		f.Grid[row][col].SetMine()
+		placed++
+	}
+	f.calculateFieldNeighborMines()
+	return f
+}
+
+// openRecursive эталонное рекурсивное открытие области для сравнения с обходом в ширину
+func openRecursive(f *Field, row, col int, opened map[Point]bool) {
+	cell := f.Grid[row][col]
+	if opened[Point{row, col}] || cell.IsMine() {
+		return
+	}
+	opened[Point{row, col}] = true
+	if cell.NeighborMines > 0 {
+		return
+	}
+	for _, p := range f.neighbors(row, col) {
+		openRecursive(f, p.Row, p.Col, opened)
+	}
+}
+
+func TestOpenCellProperties(t *testing.T) {
+	testCases := []struct {
+		name     string
+		topology Topology
+		rows     int
+		cols     int
+		mines    int
+	}{
+		{name: "small dense", topology: Square, rows: 8, cols: 8, mines: 20},
+		{name: "default", topology: Square, rows: 8, cols: 8, mines: 10},
+		{name: "wide", topology: Square, rows: 5, cols: 60, mines: 30},
+		{name: "large sparse", topology: Square, rows: 100, cols: 100, mines: 50},
+		{name: "torus", topology: Torus, rows: 30, cols: 30, mines: 40},
+		{name: "hex", topology: Hex, rows: 30, cols: 30, mines: 40},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < 20; i++ {
+				f := randomField(rng, tc.topology, tc.rows, tc.cols, tc.mines)
+				reference := make(map[Point]bool)
+
+				for click := 0; click < 50 && !f.IsWin(); click++ {
+					row, col := rng.Intn(f.Rows), rng.Intn(f.Cols)
+					if f.Grid[row][col].IsOpen || f.Grid[row][col].IsMine() {
+						continue
+					}
+					before := f.CellsOpen
+					opened := f.openNeighborCells(row, col)
+					openRecursive(f, row, col, reference)
+
+					require.Equal(t, before+len(opened), f.CellsOpen)
+					require.False(t, f.MineIsOpen, "mine opened by flood fill")
+
+					open := 0
+					for r := 0; r < f.Rows; r++ {
+						for c := 0; c < f.Cols; c++ {
+							cell := f.Grid[r][c]
+							if cell.IsOpen {
+								open++
+								require.False(t, cell.IsMine(), "mine opened by flood fill")
+							}
+							require.Equal(t, reference[Point{r, c}], cell.IsOpen)
+						}
+					}
+					require.Equal(t, open, f.CellsOpen)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenNeighborCellsNoRevisit(t *testing.T) {
+	f := randomField(rand.New(rand.NewSource(1)), Square, 100, 100, 0)
+	opened := f.openNeighborCells(50, 50)
+
+	require.Len(t, opened, 100*100)
+	require.Len(t, f.TakeChanges(), 100*100)
+	require.True(t, f.IsWin())
+	require.Empty(t, f.openNeighborCells(0, 0))
+}
+
+func BenchmarkOpenCellLargeField(b *testing.B) {
+	for _, size := range []int{8, 100, 500} {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			template := randomField(rand.New(rand.NewSource(1)), Square, size, size, size*size/100)
+			b.ReportAllocs()
+			for b.Loop() {
+				b.StopTimer()
+				f := template.clone()
+				b.StartTimer()
+				for row := 0; row < f.Rows; row++ {
+					for col := 0; col < f.Cols; col++ {
+						if !f.Grid[row][col].IsMine() {
+							f.openNeighborCells(row, col)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestChord(t *testing.T) {
+	testCases := []struct {
+		name       string
+		flags      []Point
+		cell       Point
+		err        error
+		opened     []Point
+		mineIsOpen bool
+	}{
+		{
+			name: "closed cell",
+			cell: Point{0, 0},
+			err:  ErrChordClosed,
+		},
+		{
+			name: "no flags",
+			cell: Point{1, 1},
+			err:  ErrChordFlags,
+		},
+		{
+			name:  "too many flags",
+			flags: []Point{{0, 0}, {0, 1}},
+			cell:  Point{1, 1},
+			err:   ErrChordFlags,
+		},
+		{
+			name:   "correct flag",
+			flags:  []Point{{0, 0}},
+			cell:   Point{1, 1},
+			opened: []Point{{0, 1}, {0, 2}, {1, 0}, {2, 0}},
+		},
+		{
+			name:       "wrong flag opens mine",
+			flags:      []Point{{0, 1}},
+			cell:       Point{1, 1},
+			opened:     []Point{{0, 0}},
+			mineIsOpen: true,
+		},
+		{
+			name: "outside the field",
+			cell: Point{5, 5},
+			err:  ErrFieldSize,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Мина в углу, открыта нижняя правая часть поля
+			f := buildField([]string{"*..", ".oo", ".oo"})
+			for _, p := range tc.flags {
+				require.NoError(t, f.SetFlag(p.Row, p.Col))
+			}
+
+			err := f.Chord(tc.cell.Row, tc.cell.Col)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				require.False(t, f.MineIsOpen)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.mineIsOpen, f.MineIsOpen)
+			for _, p := range tc.opened {
+				require.True(t, f.Grid[p.Row][p.Col].IsOpen)
+			}
+		})
+	}
+}
+
+func TestOpenCellOnOpenCell(t *testing.T) {
+	f := buildField([]string{"*..", ".oo", ".oo"})
+	opened := f.CellsOpen
+
+	require.NoError(t, f.OpenCell(1, 1))
+	require.Equal(t, opened, f.CellsOpen)
+	require.False(t, f.Grid[0][1].IsOpen)
+	require.False(t, f.MineIsOpen)
+}
+
+func TestSetFlagCycle(t *testing.T) {
+	testCases := []struct {
+		name      string
+		questions bool
+		values    []CellType
+		flags     []int
+	}{
+		{
+			name:      "without questions",
+			questions: false,
+			values:    []CellType{FLAG, CLOSED, FLAG},
+			flags:     []int{1, 0, 1},
+		},
+		{
+			name:      "with questions",
+			questions: true,
+			values:    []CellType{FLAG, QUESTION, CLOSED, FLAG},
+			flags:     []int{1, 0, 0, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := buildField([]string{"*..", "...", "..."})
+			f.Questions = tc.questions
+			for i, value := range tc.values {
+				require.NoError(t, f.SetFlag(2, 2))
+				require.Equal(t, value, f.Grid[2][2].Value)
+				require.Equal(t, tc.flags[i], f.Flags)
+				require.Equal(t, []*CellChange{{Row: 2, Col: 2, Value: value}}, f.TakeChanges())
+			}
+		})
+	}
+}
+
+func TestFlagsCounterOnOpen(t *testing.T) {
+	f := buildField([]string{"*...", "....", "....", "...."})
+	f.Questions = true
+	require.NoError(t, f.SetFlag(3, 3))
+	require.NoError(t, f.SetFlag(0, 0))
+	require.NoError(t, f.SetFlag(2, 2))
+	require.NoError(t, f.SetFlag(2, 2)) // знак вопроса
+	require.Equal(t, 2, f.Flags)
+
+	// Открытие области снимает флаги и знаки вопроса с открытых клеток
+	require.NoError(t, f.OpenCell(3, 0))
+	require.True(t, f.Grid[3][3].IsOpen)
+	require.True(t, f.Grid[2][2].IsOpen)
+	require.Equal(t, 1, f.Flags)
+	require.True(t, f.IsWin())
+}
+
+func TestLives(t *testing.T) {
+	f := buildField([]string{"*..", "...", "..*"})
+	f.Lives = 2
+
+	// Первая мина взрывается и тратит жизнь, игра продолжается
+	require.NoError(t, f.OpenCell(0, 0))
+	require.Equal(t, EXPLODED, f.Grid[0][0].Value)
+	require.Equal(t, 1, f.Lives)
+	require.Equal(t, 1, f.Exploded)
+	require.False(t, f.MineIsOpen)
+	require.Equal(t, []*CellChange{{Row: 0, Col: 0, Value: EXPLODED}}, f.TakeChanges())
+
+	// Взорванная мина считается известной при открытии клеток вокруг
+	require.NoError(t, f.OpenCell(1, 0))
+	require.NoError(t, f.Chord(1, 0))
+	require.True(t, f.Grid[0][1].IsOpen)
+	require.False(t, f.MineIsOpen)
+
+	// Последняя жизнь: мина открывается как в обычной игре
+	require.NoError(t, f.OpenCell(2, 2))
+	require.Equal(t, MINE, f.Grid[2][2].Value)
+	require.Equal(t, 0, f.Lives)
+	require.True(t, f.MineIsOpen)
+	require.False(t, f.IsWin())
+}
+
+func TestLivesWin(t *testing.T) {
+	f := buildField([]string{"*..", "...", "..."})
+	f.Lives = 3
+
+	require.NoError(t, f.OpenCell(0, 0))
+	require.NoError(t, f.OpenCell(2, 2))
+	require.True(t, f.IsWin())
+	require.Equal(t, 2, f.Lives)
+}
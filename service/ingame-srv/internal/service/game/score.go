@@ -0,0 +1,41 @@
+package game
+
+import "time"
+
+// Очки в режиме игры на очки
+const (
+	ScoreCell        = 10  // за каждую открытую безопасную клетку
+	ScoreFlag        = 5   // за флаг на мине, за флаг на пустой клетке столько же снимается
+	ScoreMinePenalty = 100 // за открытую мину, в режиме с жизнями за каждую взорванную
+	ScoreSpeedBonus  = 500 // максимальный бонус за скорость, начисляется только за пройденное поле
+
+	// scoreSpeedWindow за сколько нужно пройти поле, чтобы получить хоть какой-то бонус за скорость
+	scoreSpeedWindow = 5 * time.Minute
+)
+
+// Score считает очки участника по состоянию поля. elapsed - время с первого хода участника,
+// бонус за скорость линейно уменьшается от ScoreSpeedBonus до нуля за scoreSpeedWindow
+func Score(f *Field, elapsed time.Duration) int {
+	score := f.CellsOpen * ScoreCell
+	for row := 0; row < f.Rows; row++ {
+		for col := 0; col < f.Cols; col++ {
+			cell := f.Grid[row][col]
+			if cell.Value != FLAG {
+				continue
+			}
+			if cell.IsMine() {
+				score += ScoreFlag
+			} else {
+				score -= ScoreFlag
+			}
+		}
+	}
+	score -= f.Exploded * ScoreMinePenalty
+	if f.MineIsOpen {
+		score -= ScoreMinePenalty
+	}
+	if f.IsWin() && elapsed < scoreSpeedWindow {
+		score += int(int64(ScoreSpeedBonus) * int64(scoreSpeedWindow-elapsed) / int64(scoreSpeedWindow))
+	}
+	return score
+}
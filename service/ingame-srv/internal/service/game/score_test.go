@@ -0,0 +1,83 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScore(t *testing.T) {
+	testCases := []struct {
+		name    string
+		layout  []string
+		flags   []Point
+		open    []Point
+		lives   int
+		elapsed time.Duration
+		score   int
+	}{
+		{
+			name:   "closed field",
+			layout: []string{"*..", "...", "..."},
+			score:  0,
+		},
+		{
+			name:   "opened cells",
+			layout: []string{"*..", ".oo", "..."},
+			score:  2 * ScoreCell,
+		},
+		{
+			name:   "correct and wrong flags",
+			layout: []string{"*..", ".oo", "..."},
+			flags:  []Point{{0, 0}, {2, 2}},
+			score:  2*ScoreCell + ScoreFlag - ScoreFlag,
+		},
+		{
+			name:   "mine opened",
+			layout: []string{"*..", ".oo", "..."},
+			open:   []Point{{0, 0}},
+			score:  2*ScoreCell - ScoreMinePenalty,
+		},
+		{
+			name:   "mines exploded",
+			layout: []string{"*..", ".oo", "..*"},
+			open:   []Point{{0, 0}, {2, 2}},
+			lives:  3,
+			score:  2*ScoreCell - 2*ScoreMinePenalty,
+		},
+		{
+			name:    "fast win",
+			layout:  []string{"*o", "oo"},
+			flags:   []Point{{0, 0}},
+			elapsed: 0,
+			score:   3*ScoreCell + ScoreFlag + ScoreSpeedBonus,
+		},
+		{
+			name:    "half window win",
+			layout:  []string{"*o", "oo"},
+			elapsed: scoreSpeedWindow / 2,
+			score:   3*ScoreCell + ScoreSpeedBonus/2,
+		},
+		{
+			name:    "slow win",
+			layout:  []string{"*o", "oo"},
+			elapsed: time.Hour,
+			score:   3 * ScoreCell,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := buildField(tc.layout)
+			f.Lives = tc.lives
+			for _, p := range tc.flags {
+				require.NoError(t, f.SetFlag(p.Row, p.Col))
+			}
+			for _, p := range tc.open {
+				require.NoError(t, f.OpenCell(p.Row, p.Col))
+			}
+			require.Equal(t, tc.score, Score(f, tc.elapsed))
+		})
+	}
+}
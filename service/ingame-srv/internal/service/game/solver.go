@@ -0,0 +1,196 @@
+package game
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// Point координаты клетки на поле
+type Point struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// constraint ограничение от открытой клетки: среди unknown ровно mines мин
+type constraint struct {
+	unknown []Point
+	mines   int
+}
+
+// Analyze по видимому состоянию поля находит закрытые клетки, которые гарантированно безопасны или заминированы.
+// Учитываются только открытые клетки, флаги игрока не используются, так как могут быть ошибочными
+func Analyze(f *Field) (safe []Point, mines []Point) {
+	const (
+		unknown = iota
+		isSafe
+		isMine
+	)
+
+	state := make([][]int, f.Rows)
+	for row := range state {
+		state[row] = make([]int, f.Cols)
+		for col := range state[row] {
+			if f.Grid[row][col].isOpenMine() {
+				state[row][col] = isMine
+			}
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		constraints := make([]*constraint, 0)
+		for row := 0; row < f.Rows; row++ {
+			for col := 0; col < f.Cols; col++ {
+				cell := f.Grid[row][col]
+				if !cell.IsOpen || cell.isOpenMine() {
+					continue
+				}
+				count, err := strconv.Atoi(string(cell.Value))
+				if err != nil {
+					continue
+				}
+
+				c := &constraint{mines: count}
+				for _, p := range f.neighbors(row, col) {
+					if f.Grid[p.Row][p.Col].IsOpen && !f.Grid[p.Row][p.Col].isOpenMine() {
+						continue
+					}
+					switch state[p.Row][p.Col] {
+					case isMine:
+						c.mines--
+					case unknown:
+						c.unknown = append(c.unknown, p)
+					}
+				}
+				if len(c.unknown) > 0 {
+					constraints = append(constraints, c)
+				}
+			}
+		}
+
+		mark := func(points []Point, value int) {
+			for _, p := range points {
+				if state[p.Row][p.Col] == unknown {
+					state[p.Row][p.Col] = value
+					changed = true
+				}
+			}
+		}
+
+		// Ограничение само по себе: все неизвестные либо безопасны, либо мины
+		for _, c := range constraints {
+			if c.mines == 0 {
+				mark(c.unknown, isSafe)
+			} else if c.mines == len(c.unknown) {
+				mark(c.unknown, isMine)
+			}
+		}
+		if changed {
+			continue
+		}
+
+		// Ограничение a вложено в b: в разности b \ a ровно b.mines - a.mines мин
+		for _, a := range constraints {
+			for _, b := range constraints {
+				if a == b || len(a.unknown) >= len(b.unknown) || !isSubset(a.unknown, b.unknown) {
+					continue
+				}
+				diff := difference(b.unknown, a.unknown)
+				diffMines := b.mines - a.mines
+				if diffMines == 0 {
+					mark(diff, isSafe)
+				} else if diffMines == len(diff) {
+					mark(diff, isMine)
+				}
+			}
+		}
+	}
+
+	for row := 0; row < f.Rows; row++ {
+		for col := 0; col < f.Cols; col++ {
+			if f.Grid[row][col].IsOpen {
+				continue
+			}
+			switch state[row][col] {
+			case isSafe:
+				safe = append(safe, Point{row, col})
+			case isMine:
+				mines = append(mines, Point{row, col})
+			}
+		}
+	}
+	return safe, mines
+}
+
+// IsSolvable проверяет, что поле можно полностью открыть без угадывания, начиная с клетки (row, col)
+func IsSolvable(f *Field, row, col int) bool {
+	c := f.clone()
+	if err := c.OpenCell(row, col); err != nil || c.MineIsOpen {
+		return false
+	}
+
+	for !c.IsWin() {
+		safe, _ := Analyze(c)
+		if len(safe) == 0 {
+			return false
+		}
+		for _, p := range safe {
+			c.OpenCell(p.Row, p.Col)
+		}
+	}
+	return true
+}
+
+// Hint выбирает клетку для подсказки. Сначала берётся клетка, безопасность которой следует из открытых клеток,
+// если таких нет — случайная закрытая клетка без мины. Клетки с флагом не предлагаются
+func Hint(f *Field, rng *rand.Rand) (Point, bool) {
+	safe, _ := Analyze(f)
+	for _, p := range safe {
+		if f.Grid[p.Row][p.Col].Value != FLAG {
+			return p, true
+		}
+	}
+
+	candidates := make([]Point, 0)
+	for row := 0; row < f.Rows; row++ {
+		for col := 0; col < f.Cols; col++ {
+			cell := f.Grid[row][col]
+			if !cell.IsOpen && !cell.IsMine() && cell.Value != FLAG {
+				candidates = append(candidates, Point{row, col})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return Point{}, false
+	}
+	return candidates[rng.Intn(len(candidates))], true
+}
+
+func isSubset(a, b []Point) bool {
+	for _, p := range a {
+		if !containsPoint(b, p) {
+			return false
+		}
+	}
+	return true
+}
+
+func difference(a, b []Point) []Point {
+	diff := make([]Point, 0, len(a))
+	for _, p := range a {
+		if !containsPoint(b, p) {
+			diff = append(diff, p)
+		}
+	}
+	return diff
+}
+
+func containsPoint(points []Point, p Point) bool {
+	for _, point := range points {
+		if point == p {
+			return true
+		}
+	}
+	return false
+}
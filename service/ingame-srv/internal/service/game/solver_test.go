@@ -0,0 +1,146 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	testCases := []struct {
+		name   string
+		layout []string
+		safe   []Point
+		mines  []Point
+	}{
+		{
+			name:   "satisfied number frees neighbors",
+			layout: []string{"*.o", "ooo"},
+			safe:   []Point{{0, 1}},
+			mines:  []Point{{0, 0}},
+		},
+		{
+			name:   "50/50 guess",
+			layout: []string{"*.", "oo"},
+			safe:   nil,
+			mines:  nil,
+		},
+		{
+			name:   "1-2-1 pattern",
+			layout: []string{"*.*", "ooo"},
+			safe:   []Point{{0, 1}},
+			mines:  []Point{{0, 0}, {0, 2}},
+		},
+		{
+			name:   "zero opens everything around",
+			layout: []string{"...", ".o.", "..."},
+			safe:   []Point{{0, 0}, {0, 1}, {0, 2}, {1, 0}, {1, 2}, {2, 0}, {2, 1}, {2, 2}},
+			mines:  nil,
+		},
+		{
+			name:   "corner mine",
+			layout: []string{"*o", "oo"},
+			safe:   nil,
+			mines:  []Point{{0, 0}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			safe, mines := Analyze(buildField(tc.layout))
+			require.ElementsMatch(t, tc.safe, safe)
+			require.ElementsMatch(t, tc.mines, mines)
+		})
+	}
+}
+
+func TestIsSolvable(t *testing.T) {
+	require.True(t, IsSolvable(buildField([]string{"*..", "...", "..."}), 2, 2))
+	require.False(t, IsSolvable(buildField([]string{"*.", "..", "..", ".."}), 3, 0))
+}
+
+func TestCreateNoGuessField(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		field, ok := CreateNoGuessField(NewRand(int64(i)), Square, i%fieldSize, (i*3)%fieldSize, time.Second)
+		require.True(t, ok)
+		require.True(t, IsSolvable(field, i%fieldSize, (i*3)%fieldSize))
+		require.Equal(t, 0, field.CellsOpen)
+	}
+}
+
+// buildField строит поле по схеме: '*' - мина, 'o' - открытая клетка, остальные символы - закрытая клетка
+func TestHint(t *testing.T) {
+	testCases := []struct {
+		name   string
+		layout []string
+		flags  []Point
+		hint   []Point // допустимые подсказки
+		ok     bool
+	}{
+		{
+			name:   "deduced safe cell",
+			layout: []string{"*.o", "ooo"},
+			hint:   []Point{{0, 1}},
+			ok:     true,
+		},
+		{
+			name:   "guess resolved by server",
+			layout: []string{"*.", "oo"},
+			hint:   []Point{{0, 1}},
+			ok:     true,
+		},
+		{
+			name:   "flagged safe cell is skipped",
+			layout: []string{"*..", "ooo"},
+			flags:  []Point{{0, 1}},
+			hint:   []Point{{0, 2}},
+			ok:     true,
+		},
+		{
+			name:   "only mines left",
+			layout: []string{"*o", "oo"},
+			ok:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := buildField(tc.layout)
+			for _, p := range tc.flags {
+				require.NoError(t, f.SetFlag(p.Row, p.Col))
+			}
+			hint, ok := Hint(f, NewRand(1))
+			require.Equal(t, tc.ok, ok)
+			if tc.ok {
+				require.Contains(t, tc.hint, hint)
+			}
+		})
+	}
+}
+
+func buildField(layout []string) *Field {
+	f := &Field{Rows: len(layout), Cols: len(layout[0])}
+	f.Grid = make([][]*Cell, f.Rows)
+	for row, line := range layout {
+		f.Grid[row] = make([]*Cell, f.Cols)
+		for col, ch := range line {
+			f.Grid[row][col] = NewCell(CLOSED)
+			if ch == '*' {
+				f.Grid[row][col].SetMine()
+				f.Mines++
+			}
+		}
+	}
+	f.calculateFieldNeighborMines()
+	for row, line := range layout {
+		for col, ch := range line {
+			if ch == 'o' {
+				f.Grid[row][col].IsOpen = true
+				f.Grid[row][col].SetOpenValue()
+				f.CellsOpen++
+			}
+		}
+	}
+	return f
+}
@@ -0,0 +1,126 @@
+package game
+
+import "errors"
+
+const (
+	TopologySquare = "square"
+	TopologyTorus  = "torus"
+	TopologyHex    = "hex"
+)
+
+var (
+	ErrUnknownTopology = errors.New("Неизвестная топология поля")
+)
+
+// Topology определяет соседство клеток поля. Клетки всегда хранятся в прямоугольной сетке rows x cols,
+// топология задаёт только то, какие клетки считаются соседними
+type Topology interface {
+	// Name имя топологии, под которым она хранится в настройках игры
+	Name() string
+	// Neighbors возвращает соседей клетки (row, col) без повторов и без самой клетки
+	Neighbors(rows, cols, row, col int) []Point
+	// MaxNeighbors максимальное количество соседей у клетки
+	MaxNeighbors() int
+}
+
+var (
+	Square Topology = squareTopology{}
+	Torus  Topology = torusTopology{}
+	Hex    Topology = hexTopology{}
+)
+
+// topologies топологии по имени, порядок индексов используется в бинарном формате поля
+var topologies = []Topology{Square, Torus, Hex}
+
+// ParseTopology возвращает топологию по имени, пустое имя означает обычное квадратное поле
+func ParseTopology(name string) (Topology, error) {
+	if name == "" {
+		return Square, nil
+	}
+	for _, t := range topologies {
+		if t.Name() == name {
+			return t, nil
+		}
+	}
+	return nil, ErrUnknownTopology
+}
+
+// topologyID индекс топологии для бинарного формата поля
+func topologyID(name string) int {
+	for i, t := range topologies {
+		if t.Name() == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// squareTopology обычное поле, соседи - 8 клеток вокруг
+type squareTopology struct{}
+
+func (squareTopology) Name() string      { return TopologySquare }
+func (squareTopology) MaxNeighbors() int { return 8 }
+
+func (squareTopology) Neighbors(rows, cols, row, col int) []Point {
+	points := make([]Point, 0, 8)
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			if i == 0 && j == 0 {
+				continue
+			}
+			if row+i < 0 || col+j < 0 || row+i >= rows || col+j >= cols {
+				continue
+			}
+			points = append(points, Point{row + i, col + j})
+		}
+	}
+	return points
+}
+
+// torusTopology поле, склеенное по краям: у крайних клеток соседи с противоположной стороны
+type torusTopology struct{}
+
+func (torusTopology) Name() string      { return TopologyTorus }
+func (torusTopology) MaxNeighbors() int { return 8 }
+
+func (torusTopology) Neighbors(rows, cols, row, col int) []Point {
+	points := make([]Point, 0, 8)
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			p := Point{(row + i + rows) % rows, (col + j + cols) % cols}
+			// На узких полях одна и та же клетка может оказаться соседом с нескольких сторон
+			if p == (Point{row, col}) || containsPoint(points, p) {
+				continue
+			}
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// hexTopology шестиугольное поле в смещённых координатах: нечётные строки сдвинуты вправо на половину клетки
+type hexTopology struct{}
+
+func (hexTopology) Name() string      { return TopologyHex }
+func (hexTopology) MaxNeighbors() int { return 6 }
+
+var (
+	hexEvenRowOffsets = []Point{{-1, -1}, {-1, 0}, {0, -1}, {0, 1}, {1, -1}, {1, 0}}
+	hexOddRowOffsets  = []Point{{-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, 0}, {1, 1}}
+)
+
+func (hexTopology) Neighbors(rows, cols, row, col int) []Point {
+	offsets := hexEvenRowOffsets
+	if row%2 == 1 {
+		offsets = hexOddRowOffsets
+	}
+	points := make([]Point, 0, 6)
+	for _, offset := range offsets {
+		p := Point{row + offset.Row, col + offset.Col}
+		if p.Row < 0 || p.Col < 0 || p.Row >= rows || p.Col >= cols {
+			continue
+		}
+		points = append(points, p)
+	}
+	return points
+}
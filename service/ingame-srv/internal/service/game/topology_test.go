@@ -0,0 +1,131 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopologyNeighbors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		topology  Topology
+		rows      int
+		cols      int
+		cell      Point
+		neighbors []Point
+	}{
+		{
+			name:      "square corner",
+			topology:  Square,
+			rows:      8,
+			cols:      8,
+			cell:      Point{0, 0},
+			neighbors: []Point{{0, 1}, {1, 0}, {1, 1}},
+		},
+		{
+			name:      "torus corner wraps around",
+			topology:  Torus,
+			rows:      8,
+			cols:      8,
+			cell:      Point{0, 0},
+			neighbors: []Point{{7, 7}, {7, 0}, {7, 1}, {0, 7}, {0, 1}, {1, 7}, {1, 0}, {1, 1}},
+		},
+		{
+			name:      "torus narrow field has no duplicates",
+			topology:  Torus,
+			rows:      2,
+			cols:      2,
+			cell:      Point{0, 0},
+			neighbors: []Point{{0, 1}, {1, 0}, {1, 1}},
+		},
+		{
+			name:      "hex even row",
+			topology:  Hex,
+			rows:      8,
+			cols:      8,
+			cell:      Point{2, 2},
+			neighbors: []Point{{1, 1}, {1, 2}, {2, 1}, {2, 3}, {3, 1}, {3, 2}},
+		},
+		{
+			name:      "hex odd row",
+			topology:  Hex,
+			rows:      8,
+			cols:      8,
+			cell:      Point{3, 2},
+			neighbors: []Point{{2, 2}, {2, 3}, {3, 1}, {3, 3}, {4, 2}, {4, 3}},
+		},
+		{
+			name:      "hex edge",
+			topology:  Hex,
+			rows:      8,
+			cols:      8,
+			cell:      Point{0, 0},
+			neighbors: []Point{{0, 1}, {1, 0}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			neighbors := tc.topology.Neighbors(tc.rows, tc.cols, tc.cell.Row, tc.cell.Col)
+			require.ElementsMatch(t, tc.neighbors, neighbors)
+		})
+	}
+}
+
+// Соседство симметрично, все соседи лежат на поле и их не больше MaxNeighbors
+func TestTopologyInvariants(t *testing.T) {
+	for _, topology := range topologies {
+		t.Run(topology.Name(), func(t *testing.T) {
+			f := NewField()
+			for row := 0; row < f.Rows; row++ {
+				for col := 0; col < f.Cols; col++ {
+					neighbors := topology.Neighbors(f.Rows, f.Cols, row, col)
+					require.LessOrEqual(t, len(neighbors), topology.MaxNeighbors())
+					for _, p := range neighbors {
+						require.True(t, f.contains(p.Row, p.Col))
+						require.NotEqual(t, Point{row, col}, p)
+						require.Contains(t, topology.Neighbors(f.Rows, f.Cols, p.Row, p.Col), Point{row, col})
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseTopology(t *testing.T) {
+	for _, name := range []string{"", TopologySquare, TopologyTorus, TopologyHex} {
+		_, err := ParseTopology(name)
+		require.NoError(t, err)
+	}
+	_, err := ParseTopology("triangle")
+	require.ErrorIs(t, err, ErrUnknownTopology)
+}
+
+func TestCreateFieldTopology(t *testing.T) {
+	for _, topology := range topologies {
+		t.Run(topology.Name(), func(t *testing.T) {
+			for seed := int64(1); seed <= 20; seed++ {
+				f := CreateField(seed, topology, 0, 0)
+				// Первая клетка открывает область без мин и в топологии поля
+				for _, p := range append(f.neighbors(0, 0), Point{0, 0}) {
+					require.False(t, f.Grid[p.Row][p.Col].IsMine())
+				}
+				for row := 0; row < f.Rows; row++ {
+					for col := 0; col < f.Cols; col++ {
+						require.LessOrEqual(t, f.Grid[row][col].NeighborMines, topology.MaxNeighbors())
+					}
+				}
+				require.True(t, f.Reveal().Verify())
+			}
+		})
+	}
+}
+
+func TestTorusFloodFillWraps(t *testing.T) {
+	f := CreateField(3, Torus, 0, 0)
+	require.NoError(t, f.OpenCell(0, 0))
+	// Соседи через край поля открываются вместе с первой клеткой
+	require.True(t, f.Grid[f.Rows-1][f.Cols-1].IsOpen)
+	require.True(t, f.Grid[0][f.Cols-1].IsOpen)
+}
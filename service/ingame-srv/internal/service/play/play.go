@@ -0,0 +1,959 @@
+package play
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"ms4me/game_socket/internal/models"
+	storage "ms4me/game_socket/internal/redis"
+	"ms4me/game_socket/internal/service/game"
+	gameclient "ms4me/game_socket/pkg/game_client"
+	"strconv"
+	"time"
+
+	"github.com/jacute/prettylogger"
+)
+
+// snapshotInterval через сколько ходов участника вместо изменений отправляется полное состояние
+const snapshotInterval = 20
+
+// noGuessTimeout сколько времени даётся на поиск поля без угадывания
+const noGuessTimeout = time.Second
+
+// hintPenalty на сколько после подсказки участнику запрещены ходы
+const hintPenalty = 5 * time.Second
+
+// gameStartedStatus статус игры в game-srv, после которого её настройки можно сохранить
+const gameStartedStatus = "started"
+
+// scoredTimeLimit сколько длится игра на очки с первого хода. После этого она заканчивается, даже если кто-то
+// из участников не закончил поле или так и не начал играть
+const scoredTimeLimit = 10 * time.Minute
+
+// boardLockWait сколько ход в совместной игре ждёт, пока общее поле освободится от хода другого участника
+const boardLockWait = 2 * time.Second
+
+// boardLockRetry пауза между попытками захватить общее поле
+const boardLockRetry = 20 * time.Millisecond
+
+var (
+	ErrRoomNotFound    = errors.New("Игра не найдена")
+	ErrNotParticipant  = errors.New("Пользователь отсутсвует среди участников игры")
+	ErrFieldNotCreated = errors.New("Сначала откройте клетку")
+	ErrHintPenalty     = errors.New("Штраф за подсказку, подождите")
+	ErrNoHintsLeft     = errors.New("Подсказки закончились")
+	ErrNoSafeCells     = errors.New("Не осталось безопасных клеток")
+	ErrBoardFinished   = errors.New("Ваше поле уже завершено")
+	ErrBoardBusy       = errors.New("Поле занято ходом другого участника, повторите")
+)
+
+// Play ходы участников игры. Используется и http-обработчиками, и ботами, чтобы ход бота ничем не отличался от хода человека
+type Play struct {
+	log        *slog.Logger
+	redis      *storage.Redis
+	gameClient *gameclient.GameClient
+}
+
+func New(log *slog.Logger, redis *storage.Redis, gc *gameclient.GameClient) *Play {
+	return &Play{
+		log:        log,
+		redis:      redis,
+		gameClient: gc,
+	}
+}
+
+// OpenCell открывает клетку (row, col) на поле участника. На первом ходе поле генерируется
+func (p *Play) OpenCell(ctx context.Context, gameID string, userID int64, row, col int) error {
+	const op = "play.OpenCell"
+	log := p.log.With(slog.String("op", op), slog.String("game_id", gameID), slog.Int64("user_id", userID))
+
+	settings, err := p.settings(ctx, gameID)
+	if err != nil {
+		return fmt.Errorf("%s: error getting game settings: %w", op, err)
+	}
+	unlock, err := p.lockBoard(ctx, gameID, settings)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	participants, participant, err := p.participant(ctx, gameID, userID, settings)
+	if err != nil {
+		return err
+	}
+
+	// Если у игрока поля нет, то генерируем. Commitment публикуется только после успешного хода
+	firstMove := participant.Field == nil
+	commit := noCommit
+	if firstMove {
+		if !inSettingsBounds(settings, row, col) {
+			return game.ErrFieldSize
+		}
+		participant.Field = createField(settings, row, col, log)
+		commit = p.fieldCommit(ctx, gameID, participant.Field, fieldOwners(settings, participants, participant))
+	}
+	// В режиме fair стартовая область уже открыта сервером, нажатие на неё первым ходом ничего не открывает
+	openedByServer := firstMove && row >= 0 && col >= 0 && row < participant.Field.Rows && col < participant.Field.Cols &&
+		participant.Field.Grid[row][col].IsOpen
+	if !openedByServer {
+		if err := participant.Field.OpenCell(row, col); err != nil {
+			return err
+		}
+	}
+
+	return p.completeMove(ctx, log, gameID, settings, participants, participant, commit)
+}
+
+// Flag ставит или снимает флаг на клетке (row, col) поля участника
+func (p *Play) Flag(ctx context.Context, gameID string, userID int64, row, col int) error {
+	const op = "play.Flag"
+	log := p.log.With(slog.String("op", op), slog.String("game_id", gameID), slog.Int64("user_id", userID))
+
+	settings, err := p.settings(ctx, gameID)
+	if err != nil {
+		return fmt.Errorf("%s: error getting game settings: %w", op, err)
+	}
+	unlock, err := p.lockBoard(ctx, gameID, settings)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	participants, participant, err := p.participant(ctx, gameID, userID, settings)
+	if err != nil {
+		return err
+	}
+
+	commit := noCommit
+	if participant.Field == nil {
+		// Без общего сида поле генерируется только после первого открытия клетки
+		if !settings.Fair {
+			return ErrFieldNotCreated
+		}
+		if !inSettingsBounds(settings, row, col) {
+			return game.ErrFieldSize
+		}
+		participant.Field = createField(settings, row, col, log)
+		commit = p.fieldCommit(ctx, gameID, participant.Field, fieldOwners(settings, participants, participant))
+	}
+	if err := participant.Field.SetFlag(row, col); err != nil {
+		return err
+	}
+
+	return p.completeMove(ctx, log, gameID, settings, participants, participant, commit)
+}
+
+// Chord открывает клетки вокруг открытой клетки (row, col), если флагов вокруг неё столько же, сколько мин
+func (p *Play) Chord(ctx context.Context, gameID string, userID int64, row, col int) error {
+	const op = "play.Chord"
+	log := p.log.With(slog.String("op", op), slog.String("game_id", gameID), slog.Int64("user_id", userID))
+
+	settings, err := p.settings(ctx, gameID)
+	if err != nil {
+		return fmt.Errorf("%s: error getting game settings: %w", op, err)
+	}
+	unlock, err := p.lockBoard(ctx, gameID, settings)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	participants, participant, err := p.participant(ctx, gameID, userID, settings)
+	if err != nil {
+		return err
+	}
+
+	if participant.Field == nil {
+		return ErrFieldNotCreated
+	}
+	if err := participant.Field.Chord(row, col); err != nil {
+		return err
+	}
+
+	return p.completeMove(ctx, log, gameID, settings, participants, participant, noCommit)
+}
+
+// Hint открывает участнику одну безопасную клетку, расходуя подсказку из бюджета игры и накладывая штраф по времени.
+// Возвращает открытую клетку и количество оставшихся подсказок
+func (p *Play) Hint(ctx context.Context, gameID string, userID int64) (game.Point, int, error) {
+	const op = "play.Hint"
+	log := p.log.With(slog.String("op", op), slog.String("game_id", gameID), slog.Int64("user_id", userID))
+
+	settings, err := p.settings(ctx, gameID)
+	if err != nil {
+		return game.Point{}, 0, fmt.Errorf("%s: error getting game settings: %w", op, err)
+	}
+	unlock, err := p.lockBoard(ctx, gameID, settings)
+	if err != nil {
+		return game.Point{}, 0, err
+	}
+	defer unlock()
+
+	participants, participant, err := p.participant(ctx, gameID, userID, settings)
+	if err != nil {
+		return game.Point{}, 0, err
+	}
+	if participant.Field == nil {
+		return game.Point{}, 0, ErrFieldNotCreated
+	}
+	if participant.HintsUsed >= settings.Hints {
+		return game.Point{}, 0, ErrNoHintsLeft
+	}
+
+	hint, ok := game.Hint(participant.Field, game.NewRand(rand.Int64()))
+	if !ok {
+		return game.Point{}, 0, ErrNoSafeCells
+	}
+	if err := participant.Field.OpenCell(hint.Row, hint.Col); err != nil {
+		return game.Point{}, 0, fmt.Errorf("%s: error opening hint cell: %w", op, err)
+	}
+
+	participant.HintsUsed++
+	penaltyUntil := time.Now().Add(hintPenalty)
+	participant.PenaltyUntil = &penaltyUntil
+	log.Info("hint used", slog.Int("row", hint.Row), slog.Int("col", hint.Col), slog.Int("hints_used", participant.HintsUsed))
+
+	err = p.completeMove(ctx, log, gameID, settings, participants, participant, noCommit)
+	if err != nil {
+		return game.Point{}, 0, err
+	}
+	return hint, settings.Hints - participant.HintsUsed, nil
+}
+
+// CacheSettings запрашивает настройки начатой игры в game-srv и сохраняет их в redis.
+// После старта настройки поля не меняются, поэтому ходам хватает сохранённой копии
+func (p *Play) CacheSettings(ctx context.Context, gameID string) (*gameclient.GameSettings, error) {
+	settings, err := p.gameClient.GetSettings(gameID)
+	if err != nil {
+		return nil, err
+	}
+	// До старта сид поля ещё не задан, такие настройки не сохраняются
+	if settings.Status != gameStartedStatus {
+		return settings, nil
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	err = p.redis.SaveRoomSettings(ctx, gameID, data)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// settings возвращает настройки игры из redis, а если их там ещё нет, запрашивает и сохраняет
+func (p *Play) settings(ctx context.Context, gameID string) (*gameclient.GameSettings, error) {
+	data, err := p.redis.GetRoomSettings(ctx, gameID)
+	if errors.Is(err, storage.ErrNil) {
+		return p.CacheSettings(ctx, gameID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var settings gameclient.GameSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// lockBoard в совместной игре захватывает общее поле на время хода, чтобы одновременные ходы не затирали друг друга.
+// Возвращает функцию, снимающую блокировку
+func (p *Play) lockBoard(ctx context.Context, gameID string, settings *gameclient.GameSettings) (func(), error) {
+	if !settings.Coop {
+		return func() {}, nil
+	}
+
+	token := strconv.FormatInt(rand.Int64(), 36)
+	deadline := time.Now().Add(boardLockWait)
+	for {
+		ok, err := p.redis.LockBoard(ctx, gameID, token)
+		if err != nil {
+			return nil, fmt.Errorf("error locking board: %w", err)
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrBoardBusy
+		}
+		time.Sleep(boardLockRetry)
+	}
+	return func() {
+		err := p.redis.UnlockBoard(context.Background(), gameID, token)
+		if err != nil {
+			p.log.Error("error unlocking board", slog.String("game_id", gameID), prettylogger.Err(err))
+		}
+	}, nil
+}
+
+// participant загружает участников комнаты и находит среди них ходящего участника.
+// В совместной игре полем участника становится общее поле комнаты
+func (p *Play) participant(ctx context.Context, gameID string, userID int64, settings *gameclient.GameSettings) (map[string]*models.RoomParticipant, *models.RoomParticipant, error) {
+	const op = "play.participant"
+
+	exists, err := p.redis.RoomExists(ctx, gameID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return nil, nil, ErrRoomNotFound
+	}
+
+	participants, err := p.redis.GetClientsInChannel(ctx, gameID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	participant, ok := participants[strconv.FormatInt(userID, 10)]
+	if !ok {
+		return nil, nil, ErrNotParticipant
+	}
+	if participant.HasPenalty() {
+		return nil, nil, ErrHintPenalty
+	}
+	if settings.Coop {
+		board, err := p.redis.GetBoard(ctx, gameID)
+		if err != nil && !errors.Is(err, storage.ErrNil) {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		participant.Field = board
+	}
+	if participant.Field != nil && (participant.Field.MineIsOpen || participant.Field.IsWin()) {
+		return nil, nil, ErrBoardFinished
+	}
+	return participants, participant, nil
+}
+
+// createField создаёт поле участника по настройкам игры
+func createField(settings *gameclient.GameSettings, row, col int, log *slog.Logger) *game.Field {
+	field := generateField(settings, row, col, log)
+	field.Questions = settings.Questions
+	// С одной жизнью первая же мина заканчивает игру, это обычный режим
+	if settings.Lives > 1 {
+		field.Lives = settings.Lives
+	}
+	return field
+}
+
+// generateField генерирует расположение мин по настройкам игры.
+// В режиме fair поле строится по сиду игры и совпадает у всех участников, иначе генерируется от первого нажатия (row, col)
+func generateField(settings *gameclient.GameSettings, row, col int, log *slog.Logger) *game.Field {
+	topology, err := game.ParseTopology(settings.Topology)
+	if err != nil {
+		log.Warn("unknown topology, using square field", slog.String("topology", settings.Topology))
+		topology = game.Square
+	}
+	if settings.Fair && settings.Seed != nil {
+		field, _ := game.CreateSeededField(*settings.Seed, topology, settings.NoGuess)
+		return field
+	}
+	if settings.Fair {
+		log.Warn("fair game has no seed, using random field")
+	}
+
+	// Сид должен быть непредсказуем, иначе по commitment можно подобрать расположение мин
+	seed := rand.Int64()
+	if settings.NoGuess {
+		field, solvable := game.CreateNoGuessField(game.NewRand(seed), topology, row, col, noGuessTimeout)
+		if !solvable {
+			log.Warn("no-guess field generation timed out, using random field")
+		}
+		return field
+	}
+	return game.CreateField(seed, topology, row, col)
+}
+
+// fieldOwners возвращает участников, которым принадлежит поле ходящего участника. В совместной игре общее поле у всех
+func fieldOwners(settings *gameclient.GameSettings, participants map[string]*models.RoomParticipant, mover *models.RoomParticipant) []*models.RoomParticipant {
+	if !settings.Coop {
+		return []*models.RoomParticipant{mover}
+	}
+	owners := make([]*models.RoomParticipant, 0, len(participants))
+	for _, participant := range participants {
+		owners = append(owners, participant)
+	}
+	return owners
+}
+
+// commitField публикует участникам commitment нового поля и сохраняет её в game-srv для каждого владельца поля
+func (p *Play) commitField(ctx context.Context, gameID string, field *game.Field, owners []*models.RoomParticipant) error {
+	commitment := field.Commitment()
+
+	for _, owner := range owners {
+		err := p.gameClient.SaveCommitment(gameID, owner.ID, commitment)
+		if err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(&models.CommitEvent{
+			UserID:     owner.ID,
+			Commitment: commitment,
+		})
+		if err != nil {
+			return err
+		}
+		err = p.redis.PublishEvent(ctx, models.Event{
+			Type:     models.TypeCommitField,
+			UserID:   owner.ID,
+			GameID:   gameID,
+			IsPublic: false,
+			Payload:  payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldCommit откладывает публикацию commitment нового поля до сохранения хода, чтобы отклонённый ход не оставлял
+// commitment поля, которое затем будет сгенерировано заново
+func (p *Play) fieldCommit(ctx context.Context, gameID string, field *game.Field, owners []*models.RoomParticipant) func() error {
+	return func() error {
+		err := p.commitField(ctx, gameID, field, owners)
+		if err != nil {
+			return fmt.Errorf("error publishing field commitment: %w", err)
+		}
+		return nil
+	}
+}
+
+// noCommit используется для ходов, после которых новое поле не появляется
+func noCommit() error {
+	return nil
+}
+
+// inSettingsBounds проверяет, что клетка (row, col) лежит внутри поля с размерами из настроек игры
+func inSettingsBounds(settings *gameclient.GameSettings, row, col int) bool {
+	return row >= 0 && col >= 0 && row < settings.Rows && col < settings.Cols
+}
+
+// revealFields возвращает раскрытия полей всех участников, у которых поле уже сгенерировано
+func revealFields(participants map[string]*models.RoomParticipant) map[string]*game.Reveal {
+	reveals := make(map[string]*game.Reveal)
+	for key, participant := range participants {
+		if participant.Field != nil {
+			reveals[key] = participant.Field.Reveal()
+		}
+	}
+	return reveals
+}
+
+// completeMove сохраняет поле участника после хода, рассылает изменения и при проигрыше или победе завершает игру
+func (p *Play) completeMove(ctx context.Context, log *slog.Logger, gameID string, settings *gameclient.GameSettings, participants map[string]*models.RoomParticipant, mover *models.RoomParticipant, commit func() error) error {
+	if settings.Coop {
+		return p.completeCoopMove(ctx, log, gameID, participants, mover, commit)
+	}
+	if settings.Scoring {
+		return p.completeScoredMove(ctx, log, gameID, participants, mover, commit)
+	}
+
+	var loseEvent *models.LoseEvent
+	var winEvent *models.WinEvent
+	var reveals map[string]*game.Reveal
+	if mover.Field.MineIsOpen || mover.Field.IsWin() {
+		reveals = revealFields(participants)
+	}
+	// Поле испытания дня общее для всех игроков, поэтому пока день не закончился раскрытие остаётся только в game-srv
+	eventReveals := reveals
+	if settings.DailyRunning() {
+		eventReveals = nil
+	}
+	if mover.Field.MineIsOpen {
+		log.Info("user lose", slog.Int64("loser_id", mover.ID))
+		loseEvent = &models.LoseEvent{
+			LoserID:       mover.ID,
+			LoserUsername: mover.Username,
+			Reveals:       eventReveals,
+			Hints:         usedHints(participants),
+		}
+	} else if mover.Field.IsWin() {
+		log.Info("user win", slog.Int64("winner_id", mover.ID))
+		winEvent = &models.WinEvent{
+			WinnerID:       mover.ID,
+			WinnerUsername: mover.Username,
+			Reveals:        eventReveals,
+			Hints:          usedHints(participants),
+		}
+	}
+
+	mover.Moves++
+	err := p.redis.AddClientToChannel(ctx, gameID, mover.ID, mover)
+	if err != nil {
+		return fmt.Errorf("error saving participant info: %w", err)
+	}
+	if err := commit(); err != nil {
+		return err
+	}
+
+	err = p.publishMove(ctx, gameID, participants, mover)
+	if err != nil {
+		return fmt.Errorf("error publishing event: %w", err)
+	}
+
+	if loseEvent != nil {
+		resultMarshalled, err := json.Marshal(&loseEvent)
+		if err != nil {
+			return fmt.Errorf("error marshalling result: %w", err)
+		}
+		var winnerID int64 // в одиночной игре при проигрыше победителя нет
+		if winner := getParticipantWithoutOpenMine(participants); winner != nil {
+			winnerID = winner.ID
+		} else if len(participants) > 1 {
+			return errors.New("no winner in room")
+		}
+		err = p.gameClient.Close(gameID, winnerID, reveals, loseEvent.Hints, playerStats(participants))
+		if err != nil {
+			return fmt.Errorf("error closing game: %w", err)
+		}
+		err = p.redis.PublishEvent(ctx, models.Event{
+			Type:     models.TypeLoseGame,
+			UserID:   mover.ID,
+			GameID:   gameID,
+			IsPublic: false,
+			Payload:  resultMarshalled,
+		})
+		if err != nil {
+			return fmt.Errorf("error publishing event: %w", err)
+		}
+	}
+	if winEvent != nil {
+		resultMarshalled, err := json.Marshal(&winEvent)
+		if err != nil {
+			return fmt.Errorf("error marshalling result: %w", err)
+		}
+		err = p.gameClient.Close(gameID, winEvent.WinnerID, reveals, winEvent.Hints, playerStats(participants))
+		if err != nil {
+			return fmt.Errorf("error closing game: %w", err)
+		}
+		err = p.redis.PublishEvent(ctx, models.Event{
+			Type:     models.TypeWinGame,
+			UserID:   mover.ID,
+			GameID:   gameID,
+			IsPublic: false,
+			Payload:  resultMarshalled,
+		})
+		if err != nil {
+			return fmt.Errorf("error publishing event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// completeScoredMove завершает ход в игре на очки: пересчитывает очки участника, а когда игра закончена
+// (см. scoredGameFinished), заканчивает её победой участника с наибольшим счётом
+func (p *Play) completeScoredMove(ctx context.Context, log *slog.Logger, gameID string, participants map[string]*models.RoomParticipant, mover *models.RoomParticipant, commit func() error) error {
+	now := time.Now()
+	if mover.StartedAt == nil {
+		if !anyStarted(participants) {
+			p.scheduleScoredDeadline(gameID)
+		}
+		mover.StartedAt = &now
+	}
+	mover.Score = game.Score(mover.Field, now.Sub(*mover.StartedAt))
+
+	// Раскрытие считается до рассылки, как и в остальных режимах
+	finished := scoredGameFinished(participants, now)
+	var reveals map[string]*game.Reveal
+	if finished {
+		reveals = revealFields(participants)
+	}
+
+	mover.Moves++
+	err := p.redis.AddClientToChannel(ctx, gameID, mover.ID, mover)
+	if err != nil {
+		return fmt.Errorf("error saving participant info: %w", err)
+	}
+	if err := commit(); err != nil {
+		return err
+	}
+
+	err = p.publishMove(ctx, gameID, participants, mover)
+	if err != nil {
+		return fmt.Errorf("error publishing event: %w", err)
+	}
+
+	if !finished {
+		return nil
+	}
+	return p.finishScoredGame(ctx, log, gameID, participants, reveals)
+}
+
+// CheckScoredGame заканчивает игру на очки, если она уже закончена, но ход, который бы это заметил, не случится:
+// истекло время игры или вышел последний участник, который ещё играл
+func (p *Play) CheckScoredGame(ctx context.Context, gameID string) error {
+	const op = "play.CheckScoredGame"
+	log := p.log.With(slog.String("op", op), slog.String("game_id", gameID))
+
+	settings, err := p.settings(ctx, gameID)
+	if err != nil {
+		return fmt.Errorf("%s: error getting game settings: %w", op, err)
+	}
+	if !settings.Scoring || settings.Status != gameStartedStatus {
+		return nil
+	}
+	participants, err := p.redis.GetClientsInChannel(ctx, gameID)
+	if err != nil {
+		return fmt.Errorf("%s: error getting room participants: %w", op, err)
+	}
+	if len(participants) == 0 || !scoredGameFinished(participants, time.Now()) {
+		return nil
+	}
+	return p.finishScoredGame(ctx, log, gameID, participants, revealFields(participants))
+}
+
+// scheduleScoredDeadline проверяет игру на очки по истечении scoredTimeLimit после первого хода в ней
+func (p *Play) scheduleScoredDeadline(gameID string) {
+	time.AfterFunc(scoredTimeLimit, func() {
+		err := p.CheckScoredGame(context.Background(), gameID)
+		if err != nil {
+			p.log.Error("error finishing scored game by time", slog.String("game_id", gameID), prettylogger.Err(err))
+		}
+	})
+}
+
+// finishScoredGame заканчивает игру на очки победой участника с наибольшим счётом. Игра заканчивается один раз,
+// даже если это одновременно заметили ход, выход участника и истечение времени
+func (p *Play) finishScoredGame(ctx context.Context, log *slog.Logger, gameID string, participants map[string]*models.RoomParticipant, reveals map[string]*game.Reveal) error {
+	first, err := p.redis.MarkRoomFinished(ctx, gameID)
+	if err != nil {
+		return fmt.Errorf("error marking game finished: %w", err)
+	}
+	if !first {
+		return nil
+	}
+
+	winner := bestScore(participants)
+	log.Info("user win by score", slog.Int64("winner_id", winner.ID), slog.Int("score", winner.Score))
+	winEvent := &models.WinEvent{
+		WinnerID:       winner.ID,
+		WinnerUsername: winner.Username,
+		Reveals:        reveals,
+		Hints:          usedHints(participants),
+		Scores:         scores(participants),
+	}
+	resultMarshalled, err := json.Marshal(winEvent)
+	if err != nil {
+		return fmt.Errorf("error marshalling result: %w", err)
+	}
+	err = p.gameClient.Close(gameID, winEvent.WinnerID, winEvent.Reveals, winEvent.Hints, playerStats(participants))
+	if err != nil {
+		return fmt.Errorf("error closing game: %w", err)
+	}
+	err = p.redis.PublishEvent(ctx, models.Event{
+		Type:     models.TypeWinGame,
+		UserID:   winner.ID,
+		GameID:   gameID,
+		IsPublic: false,
+		Payload:  resultMarshalled,
+	})
+	if err != nil {
+		return fmt.Errorf("error publishing event: %w", err)
+	}
+	return nil
+}
+
+// scoredGameFinished проверяет, что игра на очки закончена: поля всех оставшихся в комнате участников пройдены
+// или взорваны, либо с первого хода в игре прошло scoredTimeLimit. Вышедших участников в комнате уже нет
+func scoredGameFinished(participants map[string]*models.RoomParticipant, now time.Time) bool {
+	var firstMove *time.Time
+	for _, participant := range participants {
+		if participant.StartedAt != nil && (firstMove == nil || participant.StartedAt.Before(*firstMove)) {
+			firstMove = participant.StartedAt
+		}
+	}
+	if firstMove == nil {
+		return false
+	}
+	if !now.Before(firstMove.Add(scoredTimeLimit)) {
+		return true
+	}
+	for _, participant := range participants {
+		if participant.Field == nil || !(participant.Field.MineIsOpen || participant.Field.IsWin()) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyStarted проверяет, что кто-то из участников уже сделал ход
+func anyStarted(participants map[string]*models.RoomParticipant) bool {
+	for _, participant := range participants {
+		if participant.StartedAt != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// completeCoopMove завершает ход в совместной игре: сохраняет общее поле, засчитывает открытые клетки ходящему участнику
+// и рассылает изменения всем участникам. Подрыв или прохождение общего поля заканчивает игру для всей команды
+func (p *Play) completeCoopMove(ctx context.Context, log *slog.Logger, gameID string, participants map[string]*models.RoomParticipant, mover *models.RoomParticipant, commit func() error) error {
+	// Общее поле хранится отдельно от участников, в записи участника остаются только его итоги
+	board := mover.Field
+	mover.Field = nil
+	changes := board.TakeChanges()
+	mover.CellsOpened += openedCells(changes)
+	mover.Moves++
+
+	// Раскрытие считается до рассылки, так как при рассылке расположение мин на поле маскируется
+	var result any
+	var resultType models.EventType
+	var reveals map[string]*game.Reveal
+	if board.MineIsOpen || board.IsWin() {
+		reveals = make(map[string]*game.Reveal)
+		for key := range participants {
+			reveals[key] = board.Reveal()
+		}
+	}
+	if board.MineIsOpen {
+		log.Info("team lose", slog.Int64("loser_id", mover.ID))
+		resultType = models.TypeLoseGame
+		result = &models.LoseEvent{
+			LoserID:       mover.ID,
+			LoserUsername: mover.Username,
+			Reveals:       reveals,
+			Hints:         usedHints(participants),
+			Team:          true,
+		}
+	} else if board.IsWin() {
+		log.Info("team win", slog.Int64("winner_id", mover.ID))
+		resultType = models.TypeWinGame
+		result = &models.WinEvent{
+			WinnerID:       mover.ID,
+			WinnerUsername: mover.Username,
+			Reveals:        reveals,
+			Hints:          usedHints(participants),
+			Team:           true,
+		}
+	}
+
+	err := p.redis.SaveBoard(ctx, gameID, board)
+	if err != nil {
+		return fmt.Errorf("error saving board: %w", err)
+	}
+	err = p.redis.AddClientToChannel(ctx, gameID, mover.ID, mover)
+	if err != nil {
+		return fmt.Errorf("error saving participant info: %w", err)
+	}
+	if err := commit(); err != nil {
+		return err
+	}
+
+	err = p.publishCoopMove(ctx, gameID, participants, mover, board, changes)
+	if err != nil {
+		return fmt.Errorf("error publishing event: %w", err)
+	}
+
+	if result == nil {
+		return nil
+	}
+	resultMarshalled, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("error marshalling result: %w", err)
+	}
+	// При проигрыше команды победителя нет, при победе победителем записывается открывший последнюю клетку
+	var winnerID int64
+	if resultType == models.TypeWinGame {
+		winnerID = mover.ID
+	}
+	err = p.gameClient.Close(gameID, winnerID, reveals, usedHints(participants), playerStats(participants))
+	if err != nil {
+		return fmt.Errorf("error closing game: %w", err)
+	}
+	err = p.redis.PublishEvent(ctx, models.Event{
+		Type:     resultType,
+		UserID:   mover.ID,
+		GameID:   gameID,
+		IsPublic: false,
+		Payload:  resultMarshalled,
+	})
+	if err != nil {
+		return fmt.Errorf("error publishing event: %w", err)
+	}
+	return nil
+}
+
+// openedCells считает безопасные клетки, открытые ходом
+func openedCells(changes []*game.CellChange) int {
+	c := 0
+	for _, change := range changes {
+		if _, err := strconv.Atoi(string(change.Value)); err == nil {
+			c++
+		}
+	}
+	return c
+}
+
+// bestScore возвращает участника с наибольшим счётом, при равенстве побеждает сделавший меньше ходов
+func bestScore(participants map[string]*models.RoomParticipant) *models.RoomParticipant {
+	var best *models.RoomParticipant
+	for _, participant := range participants {
+		if best == nil || participant.Score > best.Score ||
+			(participant.Score == best.Score && participant.Moves < best.Moves) ||
+			(participant.Score == best.Score && participant.Moves == best.Moves && participant.ID < best.ID) {
+			best = participant
+		}
+	}
+	return best
+}
+
+// scores возвращает счёт по id участника
+func scores(participants map[string]*models.RoomParticipant) map[string]int {
+	result := make(map[string]int)
+	for key, participant := range participants {
+		result[key] = participant.Score
+	}
+	return result
+}
+
+// playerStats возвращает итоги участников по id. В совместной игре открытые клетки засчитываются по ходам участника,
+// в остальных играх это открытые клетки его поля
+func playerStats(participants map[string]*models.RoomParticipant) map[string]*gameclient.PlayerStats {
+	stats := make(map[string]*gameclient.PlayerStats)
+	for key, participant := range participants {
+		opened := participant.CellsOpened
+		if participant.Field != nil {
+			opened = participant.Field.CellsOpen
+		}
+		stats[key] = &gameclient.PlayerStats{Moves: participant.Moves, CellsOpened: opened}
+	}
+	return stats
+}
+
+// usedHints возвращает количество использованных подсказок по id участника
+func usedHints(participants map[string]*models.RoomParticipant) map[string]int {
+	hints := make(map[string]int)
+	for key, participant := range participants {
+		hints[key] = participant.HintsUsed
+	}
+	return hints
+}
+
+// publishMove публикует результат хода участника. Обычно в событии передаются только изменённые клетки,
+// а на первом ходе и каждые snapshotInterval ходов — полное состояние полей всех участников
+func (p *Play) publishMove(ctx context.Context, gameID string, participants map[string]*models.RoomParticipant, mover *models.RoomParticipant) error {
+	changes := mover.Field.TakeChanges()
+
+	event := models.Event{
+		Type:     models.TypeDiffGame,
+		UserID:   mover.ID,
+		GameID:   gameID,
+		IsPublic: false,
+	}
+	var err error
+	if mover.Moves == 1 || mover.Moves%snapshotInterval == 0 {
+		event.Type = models.TypeClickGame
+		event.Payload, err = MarshalGameData(participants)
+	} else {
+		event.Payload, err = json.Marshal(map[string]*models.FieldDiff{
+			strconv.FormatInt(mover.ID, 10): {
+				Changes:    changes,
+				CellsOpen:  mover.Field.CellsOpen,
+				MineIsOpen: mover.Field.MineIsOpen,
+				Flags:      mover.Field.Flags,
+				Score:      mover.Score,
+				Lives:      mover.Field.Lives,
+				Exploded:   mover.Field.Exploded,
+			},
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return p.redis.PublishEvent(ctx, event)
+}
+
+// publishCoopMove публикует ход на общем поле. Общее поле показывается как поле каждого участника,
+// а в изменениях указан сделавший ход участник, чтобы клиенты могли его подсветить
+func (p *Play) publishCoopMove(ctx context.Context, gameID string, participants map[string]*models.RoomParticipant, mover *models.RoomParticipant, board *game.Field, changes []*game.CellChange) error {
+	event := models.Event{
+		Type:     models.TypeDiffGame,
+		UserID:   mover.ID,
+		GameID:   gameID,
+		IsPublic: false,
+	}
+	var err error
+	if mover.Moves == 1 || mover.Moves%snapshotInterval == 0 {
+		event.Type = models.TypeClickGame
+		event.Payload, err = MarshalGameData(WithBoard(participants, board))
+	} else {
+		diff := &models.FieldDiff{
+			Changes:    changes,
+			CellsOpen:  board.CellsOpen,
+			MineIsOpen: board.MineIsOpen,
+			Flags:      board.Flags,
+			Lives:      board.Lives,
+			Exploded:   board.Exploded,
+			MovedBy:    mover.ID,
+		}
+		diffs := make(map[string]*models.FieldDiff, len(participants))
+		for key := range participants {
+			diffs[key] = diff
+		}
+		event.Payload, err = json.Marshal(diffs)
+	}
+	if err != nil {
+		return err
+	}
+
+	return p.redis.PublishEvent(ctx, event)
+}
+
+// WithBoard возвращает копии участников, у каждого из которых полем выставлено общее поле совместной игры.
+// Участники копируются, чтобы общее поле не попало в их записи и итоги
+func WithBoard(participants map[string]*models.RoomParticipant, board *game.Field) map[string]*models.RoomParticipant {
+	mirrored := make(map[string]*models.RoomParticipant, len(participants))
+	for key, participant := range participants {
+		participantCopy := *participant
+		participantCopy.Field = board
+		mirrored[key] = &participantCopy
+	}
+	return mirrored
+}
+
+// MarshalGameData подготаливает json с данными по игре для отправки клиенту, маскируя поля json, которые не должны передаваться (расположения мин)
+func MarshalGameData(participants map[string]*models.RoomParticipant) ([]byte, error) {
+	arrParticipants := make([]*models.RoomParticipant, 0)
+	for _, participant := range participants {
+		// Маскируется копия, чтобы поля участников оставались пригодными для раскрытия и дальнейших ходов
+		participantCopy := *participant
+		if participant.Field != nil {
+			participantCopy.Field = participant.Field.Masked()
+		}
+		arrParticipants = append(arrParticipants, &participantCopy)
+	}
+	data, err := json.Marshal(arrParticipants)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// getParticipantWithoutOpenMine возвращает участника, который ещё не проиграл. В режиме с жизнями из живых участников
+// выбирается взорвавший меньше мин, затем открывший больше клеток
+func getParticipantWithoutOpenMine(participants map[string]*models.RoomParticipant) *models.RoomParticipant {
+	var best *models.RoomParticipant
+	var bestExploded, bestOpen int
+	for _, rp := range participants {
+		exploded, open := 0, 0
+		if rp.Field != nil {
+			if rp.Field.MineIsOpen {
+				continue
+			}
+			exploded, open = rp.Field.Exploded, rp.Field.CellsOpen
+		}
+		if best == nil || exploded < bestExploded ||
+			(exploded == bestExploded && open > bestOpen) ||
+			(exploded == bestExploded && open == bestOpen && rp.ID < best.ID) {
+			best, bestExploded, bestOpen = rp, exploded, open
+		}
+	}
+	return best
+}
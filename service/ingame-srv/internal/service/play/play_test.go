@@ -0,0 +1,87 @@
+package play
+
+import (
+	"ms4me/game_socket/internal/models"
+	"ms4me/game_socket/internal/service/game"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoredGameFinished(t *testing.T) {
+	now := time.Now()
+	started := now.Add(-time.Minute)
+	expired := now.Add(-scoredTimeLimit)
+
+	won := &game.Field{Rows: 2, Cols: 2, Mines: 1, CellsOpen: 3}
+	exploded := &game.Field{Rows: 2, Cols: 2, Mines: 1, CellsOpen: 1, MineIsOpen: true}
+	playing := &game.Field{Rows: 2, Cols: 2, Mines: 1, CellsOpen: 1}
+
+	testCases := []struct {
+		name         string
+		participants []*models.RoomParticipant
+		want         bool
+	}{
+		{
+			name: "nobody moved",
+			participants: []*models.RoomParticipant{
+				{ID: 1}, {ID: 2},
+			},
+			want: false,
+		},
+		{
+			name: "all fields finished",
+			participants: []*models.RoomParticipant{
+				{ID: 1, StartedAt: &started, Field: won}, {ID: 2, StartedAt: &started, Field: exploded},
+			},
+			want: true,
+		},
+		{
+			name: "someone still playing",
+			participants: []*models.RoomParticipant{
+				{ID: 1, StartedAt: &started, Field: won}, {ID: 2, StartedAt: &started, Field: playing},
+			},
+			want: false,
+		},
+		{
+			name: "someone never started",
+			participants: []*models.RoomParticipant{
+				{ID: 1, StartedAt: &started, Field: won}, {ID: 2},
+			},
+			want: false,
+		},
+		{
+			name: "time is up for a player who never started",
+			participants: []*models.RoomParticipant{
+				{ID: 1, StartedAt: &expired, Field: won}, {ID: 2},
+			},
+			want: true,
+		},
+		{
+			name: "time is up while someone is playing",
+			participants: []*models.RoomParticipant{
+				{ID: 1, StartedAt: &started, Field: playing}, {ID: 2, StartedAt: &expired, Field: exploded},
+			},
+			want: true,
+		},
+		{
+			name: "the only one left has finished",
+			participants: []*models.RoomParticipant{
+				{ID: 1, StartedAt: &started, Field: won},
+			},
+			want: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			participants := make(map[string]*models.RoomParticipant, len(tc.participants))
+			for _, participant := range tc.participants {
+				participants[strconv.FormatInt(participant.ID, 10)] = participant
+			}
+			require.Equal(t, tc.want, scoredGameFinished(participants, now))
+		})
+	}
+}
@@ -19,16 +19,27 @@ const (
 	JoinRoomEventType   EventType = "JOIN_ROOM"
 	ExitRoomEventType   EventType = "EXIT_ROOM"
 	AuthEventType       EventType = "AUTH"
+	ResyncEventType     EventType = "RESYNC"
 
-	StartGameEventType EventType = "START_GAME"
-	ClickGameEventType EventType = "OPEN_CELL"
-	LoseGameEventType  EventType = "LOSE_GAME"
-	WinGameEventType   EventType = "WIN_GAME"
+	StartGameEventType   EventType = "START_GAME"
+	ClickGameEventType   EventType = "OPEN_CELL"
+	LoseGameEventType    EventType = "LOSE_GAME"
+	WinGameEventType     EventType = "WIN_GAME"
+	CommitFieldEventType EventType = "FIELD_COMMIT"
 
-	NewMessageEventType EventType = "NEW_MESSAGE"
+	NewMessageEventType     EventType = "NEW_MESSAGE"
+	MessageEditedEventType  EventType = "MESSAGE_EDITED"
+	MessageDeletedEventType EventType = "MESSAGE_DELETED"
+	LobbyMessageEventType   EventType = "LOBBY_MESSAGE"
+	EmoteEventType          EventType = "EMOTE"
+	DirectMessageEventType  EventType = "DIRECT_MESSAGE"
+	InviteEventType         EventType = "INVITE"
+
+	TournamentRoundEventType EventType = "TOURNAMENT_ROUND"
 )
 
 type Response struct {
+	Seq       int64           `json:"seq,omitempty"` // порядковый номер события в комнате
 	Status    string          `json:"status"`
 	EventType EventType       `json:"event_type,omitempty"`
 	Payload   json.RawMessage `json:"payload,omitempty"`
@@ -12,7 +12,8 @@ import (
 )
 
 type Credentials struct {
-	Token string `json:"token"`
+	Token   string `json:"token"`
+	LastSeq *int64 `json:"last_seq,omitempty"` // последнее полученное клиентом событие комнаты
 }
 
 type contextKey string
@@ -21,34 +22,34 @@ const userContextKey contextKey = "user"
 const roomContextKey contextKey = "room"
 
 // auth middleware validate token
-func (s *Server) auth(ctx context.Context, conn *websocket.Conn) (*models.User, error) {
+func (s *Server) auth(ctx context.Context, conn *websocket.Conn) (*models.User, *Credentials, error) {
 	const op = "ws.auth"
 	log := s.log.With(slog.String("op", op))
 
 	msg, err := s.read(conn)
 	if err != nil {
-		return nil, ErrRead
+		return nil, nil, ErrRead
 	}
 
 	var creds Credentials
 	if err := json.Unmarshal(msg, &creds); err != nil {
-		return nil, ErrUnmarshalJSON
+		return nil, nil, ErrUnmarshalJSON
 	}
 
 	claims, err := jwt.VerifyToken(creds.Token, []byte(s.cfg.JwtSecret))
 	if err != nil {
 		log.Info("error verifying token", prettylogger.Err(err))
-		return nil, ErrAuthError
+		return nil, nil, ErrAuthError
 	}
 
 	userID, ok := claims["user_id"].(float64)
 	if !ok {
-		return nil, ErrAuthError
+		return nil, nil, ErrAuthError
 	}
 	username, ok := claims["username"].(string)
 
 	return &models.User{
 		ID:       int64(userID),
 		Username: username,
-	}, nil
+	}, &creds, nil
 }
@@ -28,7 +28,7 @@ func (s *Server) Handle(conn *websocket.Conn) {
 
 	log.Debug("new connection", slog.String("origin", conn.RemoteAddr().String()))
 
-	user, err := s.auth(ctx, conn)
+	user, creds, err := s.auth(ctx, conn)
 	if err != nil {
 		_, err = conn.Write(dto_ws.Error(err, dto_ws.AuthEventType).Serialize())
 		if err != nil {
@@ -56,24 +56,60 @@ func (s *Server) Handle(conn *websocket.Conn) {
 	}
 	client := &Client{ctx: ctx, conn: conn, user: user, requestID: requestID, room: id}
 
+	// Живые события, пришедшие во время повтора пропущенных, ждут на writeMu и уходят клиенту после них
+	client.writeMu.Lock()
 	s.usersMu.Lock()
 	s.users[user.ID] = append(s.users[user.ID], client)
 	s.usersMu.Unlock()
 	err = s.write(conn, dto_ws.OK("Authenticated successfully", dto_ws.AuthEventType).Serialize())
 	if err != nil {
+		client.writeMu.Unlock()
 		log.Error("failed to send message", prettylogger.Err(err))
-		err = conn.Close()
+		err = s.disconnect(client)
 		if err != nil {
 			log.Error("failed to close connect", prettylogger.Err(err))
 		}
-		s.usersMu.Unlock()
 		return
 	}
+	if id != "" && creds.LastSeq != nil {
+		s.replay(client, *creds.LastSeq)
+	}
+	client.writeMu.Unlock()
 
 	go s.readLoop(client)
 	s.pingLoop(client)
 }
 
+// replay отправляет клиенту события комнаты, пропущенные с момента lastSeq.
+// Если часть событий уже вытеснена из буфера, клиент получает RESYNC и должен заново запросить состояние игры.
+// Вызывается под client.writeMu. Клиент уже подписан на живые события, поэтому событие может прийти
+// и повтором, и вживую, клиент отбрасывает уже полученные seq
+func (s *Server) replay(client *Client, lastSeq int64) {
+	const op = "ws.replay"
+	log := s.log.With(slog.String("op", op), slog.String("request_id", client.requestID), slog.Int64("user_id", client.user.ID))
+
+	events, complete, err := s.redis.ReadEventsAfter(client.ctx, client.room, lastSeq)
+	if err != nil {
+		log.Error("error reading room events", prettylogger.Err(err))
+		complete = false
+	}
+	if !complete {
+		err = s.write(client.conn, dto_ws.OK("Events were lost, refetch game state", dto_ws.ResyncEventType).Serialize())
+		if err != nil {
+			log.Error("failed to send resync", prettylogger.Err(err))
+		}
+		return
+	}
+	for _, event := range events {
+		err = s.write(client.conn, event.Serialize())
+		if err != nil {
+			log.Error("failed to replay event", slog.Int64("seq", event.Seq), prettylogger.Err(err))
+			return
+		}
+	}
+	log.Debug("events replayed", slog.Int64("last_seq", lastSeq), slog.Int("count", len(events)))
+}
+
 func (s *Server) Close() error {
 	s.usersMu.Lock()
 	defer s.usersMu.Unlock()
@@ -128,6 +164,19 @@ func (s *Server) write(conn *websocket.Conn, msg []byte) error {
 	return nil
 }
 
+// send пишет событие клиенту, не пересекаясь с другими записями в его соединение
+func (s *Server) send(client *Client, msg []byte) error {
+	client.writeMu.Lock()
+	defer client.writeMu.Unlock()
+	return s.write(client.conn, msg)
+}
+
+func (s *Server) ping(client *Client) error {
+	client.writeMu.Lock()
+	defer client.writeMu.Unlock()
+	return websocket.Message.Send(client.conn, "ping")
+}
+
 func (s *Server) pingLoop(client *Client) {
 	const op = "ws.pingLoop"
 	log := s.log.With(slog.String("op", op), slog.String("request_id", client.requestID), slog.Int64("user_id", client.user.ID))
@@ -140,7 +189,7 @@ func (s *Server) pingLoop(client *Client) {
 		case <-client.ctx.Done():
 			return
 		case <-ticker.C:
-			err := websocket.Message.Send(client.conn, "ping")
+			err := s.ping(client)
 			if err != nil {
 				log.Debug("ping failed, closing connection", prettylogger.Err(err))
 				s.disconnect(client)
@@ -161,7 +210,7 @@ func (s *Server) CheckRoomConn(userID int64, room string) bool {
 	connExists := false
 	for _, client := range clients {
 		if client.room == room {
-			err := websocket.Message.Send(client.conn, "ping")
+			err := s.ping(client)
 			if err != nil {
 				log.Debug("ping failed, closing connection", prettylogger.Err(err))
 				s.disconnect(client)
@@ -179,6 +228,20 @@ func (s *Server) CheckRoomConn(userID int64, room string) bool {
 	return true
 }
 
+// Online возвращает тех из пользователей ids, у кого есть хотя бы одно подключение по вебсокету
+func (s *Server) Online(ids []int64) []int64 {
+	s.usersMu.Lock()
+	defer s.usersMu.Unlock()
+
+	online := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if len(s.users[id]) > 0 {
+			online = append(online, id)
+		}
+	}
+	return online
+}
+
 func (s *Server) disconnect(client *Client) error {
 	const op = "ws.disconnect"
 	log := s.log.With(slog.String("op", op), slog.String("request_id", client.requestID), slog.Int64("user_id", client.user.ID))
@@ -224,7 +287,7 @@ func (s *Server) MulticastEvent(roomID string, users []int, res *dto_ws.Response
 				wg.Add(1)
 				go func() {
 					for i := 0; i < multicastWriteRetriesCount; i++ {
-						err := s.write(client.conn, res.Serialize())
+						err := s.send(client, res.Serialize())
 						if err != nil {
 							log.Error("error writing event to client", slog.Any("event", res))
 							time.Sleep(multicastWriteRetriesTimeoutMLS * time.Millisecond)
@@ -271,7 +334,7 @@ func (s *Server) BroadcastEvent(res *dto_ws.Response) {
 	for _, clients := range s.users {
 		for _, client := range clients {
 			if client.room == "" {
-				err := s.write(client.conn, res.Serialize())
+				err := s.send(client, res.Serialize())
 				if err != nil {
 					log.Error("error writing event to client", slog.Any("event", res))
 					continue
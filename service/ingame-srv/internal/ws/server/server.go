@@ -29,6 +29,7 @@ type Client struct {
 	user      *models.User
 	room      string
 	requestID string
+	writeMu   sync.Mutex // записи в conn идут по очереди, иначе фреймы разных событий перемешиваются
 }
 
 var (
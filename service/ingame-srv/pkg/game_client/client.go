@@ -2,18 +2,26 @@ package gameclient
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"ms4me/game_socket/internal/config"
 	"ms4me/game_socket/internal/http/dto"
+	"ms4me/game_socket/internal/models"
+	"ms4me/game_socket/internal/service/game"
 	"net/http"
 	"net/url"
 
 	"github.com/go-chi/render"
 )
 
+const gameSettingsEndpoint = "/api/v1/internal/game/%s"
 const gameStatusEndpoint = "/api/v1/internal/game/%s/status"
 const gameCloseEndpoint = "/api/v1/internal/game/%s/close"
+const gameCommitmentEndpoint = "/api/v1/internal/game/%s/commitment"
+const gameChatEndpoint = "/api/v1/internal/game/%s/chat"
+
+var ErrGameNotFound = errors.New("игра не найдена")
 
 type GameClient struct {
 	URL *url.URL
@@ -30,12 +38,12 @@ func New(cfg *config.GameConfig) *GameClient {
 }
 
 func (c *GameClient) GetStatus(gameID string) (string, error) {
-	url := c.URL
+	url := *c.URL // копия, клиент используется из нескольких горутин ботов
 	url.Path = fmt.Sprintf(gameStatusEndpoint, gameID)
 
 	client := &http.Client{}
 
-	resp, err := client.Get(c.URL.String())
+	resp, err := client.Get(url.String())
 	if err != nil {
 		return "", err
 	}
@@ -56,13 +64,144 @@ func (c *GameClient) GetStatus(gameID string) (string, error) {
 	return res.Result, nil
 }
 
-func (c *GameClient) Close(gameID string, winnerID int64) error {
-	url := c.URL
+func (c *GameClient) GetSettings(gameID string) (*GameSettings, error) {
+	url := *c.URL
+	url.Path = fmt.Sprintf(gameSettingsEndpoint, gameID)
+
+	client := &http.Client{}
+
+	resp, err := client.Get(url.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res GameSettingsResponse
+	if err := render.DecodeJSON(resp.Body, &res); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if res.Status == dto.StatusError {
+		return nil, errors.New(res.Error)
+	}
+
+	return res.Game, nil
+}
+
+// GetPlayers возвращает участников игры, записанных в game-srv. Работает и после удаления комнаты
+func (c *GameClient) GetPlayers(gameID string) ([]*Player, error) {
+	url := *c.URL
+	url.Path = fmt.Sprintf(gameSettingsEndpoint, gameID)
+
+	client := &http.Client{}
+
+	resp, err := client.Get(url.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res GamePlayersResponse
+	if err := render.DecodeJSON(resp.Body, &res); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrGameNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if res.Status == dto.StatusError {
+		return nil, errors.New(res.Error)
+	}
+
+	return res.Game.Players, nil
+}
+
+func (c *GameClient) Close(gameID string, winnerID int64, reveals map[string]*game.Reveal, hints map[string]int, stats map[string]*PlayerStats) error {
+	url := *c.URL
 	url.Path = fmt.Sprintf(gameCloseEndpoint, gameID)
 
+	body, err := json.Marshal(&CloseGameRequest{
+		WinnerID: winnerID,
+		Reveals:  reveals,
+		Hints:    hints,
+		Stats:    stats,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.post(url.String(), body)
+}
+
+// SaveCommitment сохраняет в game-srv commitment поля участника
+func (c *GameClient) SaveCommitment(gameID string, userID int64, commitment string) error {
+	url := *c.URL
+	url.Path = fmt.Sprintf(gameCommitmentEndpoint, gameID)
+
+	body, err := json.Marshal(&SaveCommitmentRequest{
+		UserID:     userID,
+		Commitment: commitment,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.post(url.String(), body)
+}
+
+// ArchiveChat сохраняет сообщения чата игры в game-srv
+func (c *GameClient) ArchiveChat(gameID string, messages []*models.Message) error {
+	url := *c.URL
+	url.Path = fmt.Sprintf(gameChatEndpoint, gameID)
+
+	body, err := json.Marshal(&ArchiveChatRequest{
+		Messages: messages,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.post(url.String(), body)
+}
+
+// GetChat возвращает сохранённую в game-srv историю чата игры
+func (c *GameClient) GetChat(gameID string) ([]*models.Message, error) {
+	url := *c.URL
+	url.Path = fmt.Sprintf(gameChatEndpoint, gameID)
+
+	client := &http.Client{}
+
+	resp, err := client.Get(url.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res GetChatResponse
+	if err := render.DecodeJSON(resp.Body, &res); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if res.Status == dto.StatusError {
+		return nil, errors.New(res.Error)
+	}
+
+	return res.Messages, nil
+}
+
+func (c *GameClient) post(url string, body []byte) error {
 	client := &http.Client{}
 
-	resp, err := client.Post(c.URL.String(), "application/json", bytes.NewBuffer([]byte(fmt.Sprintf("{\"winner_id\":%d}", winnerID))))
+	resp, err := client.Post(url, "application/json", bytes.NewBuffer(body))
 	if err != nil {
 		return err
 	}
@@ -1,8 +1,82 @@
 package gameclient
 
-import "ms4me/game_socket/internal/http/dto"
+import (
+	"ms4me/game_socket/internal/http/dto"
+	"ms4me/game_socket/internal/models"
+	"ms4me/game_socket/internal/service/game"
+	"time"
+)
 
 type GameStatusResponse struct {
 	dto.Response
 	Result string `json:"result"`
 }
+
+// GameSettings параметры игры из game-srv, влияющие на игровое поле
+type GameSettings struct {
+	ID        string     `json:"id"`
+	Rows      int        `json:"rows"`
+	Cols      int        `json:"cols"`
+	Mines     int        `json:"mines"`
+	Status    string     `json:"status"`
+	NoGuess   bool       `json:"no_guess"`
+	Fair      bool       `json:"fair"`
+	Seed      *int64     `json:"seed,omitempty"`     // сид поля, задаётся при старте игры в режиме fair
+	Hints     int        `json:"hints"`              // количество подсказок на участника
+	Topology  string     `json:"topology,omitempty"` // топология поля, пусто для обычного поля
+	Questions bool       `json:"questions"`          // включены ли знаки вопроса
+	Scoring   bool       `json:"scoring"`            // победитель определяется по очкам
+	Lives     int        `json:"lives"`              // сколько мин может открыть участник, последняя заканчивает его игру
+	Coop      bool       `json:"coop"`               // совместная игра на одном общем поле
+	Daily     *time.Time `json:"daily,omitempty"`    // день ежедневного испытания, если игра - его попытка
+}
+
+// DailyRunning проверяет, что игра - попытка ежедневного испытания, которое ещё идёт
+func (s *GameSettings) DailyRunning() bool {
+	return s.Daily != nil && time.Now().UTC().Before(s.Daily.AddDate(0, 0, 1))
+}
+
+type GameSettingsResponse struct {
+	dto.Response
+	Game *GameSettings `json:"game"`
+}
+
+// Player участник игры по данным game-srv
+type Player struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type GamePlayersResponse struct {
+	dto.Response
+	Game *struct {
+		Players []*Player `json:"players"`
+	} `json:"game"`
+}
+
+// PlayerStats итоги участника за игру
+type PlayerStats struct {
+	Moves       int `json:"moves"`
+	CellsOpened int `json:"cells_opened"`
+}
+
+type CloseGameRequest struct {
+	WinnerID int64                   `json:"winner_id"` // 0, если победителя нет
+	Reveals  map[string]*game.Reveal `json:"reveals,omitempty"`
+	Hints    map[string]int          `json:"hints,omitempty"`
+	Stats    map[string]*PlayerStats `json:"stats,omitempty"` // итоги участников по id
+}
+
+type SaveCommitmentRequest struct {
+	UserID     int64  `json:"user_id"`
+	Commitment string `json:"commitment"`
+}
+
+type ArchiveChatRequest struct {
+	Messages []*models.Message `json:"messages"`
+}
+
+type GetChatResponse struct {
+	dto.Response
+	Messages []*models.Message `json:"messages"`
+}
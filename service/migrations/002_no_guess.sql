@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS no_guess BOOLEAN DEFAULT false;
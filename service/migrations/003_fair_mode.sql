@@ -0,0 +1,2 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS fair BOOLEAN DEFAULT false;
+ALTER TABLE games ADD COLUMN IF NOT EXISTS seed BIGINT;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS field_commitments (
+    game_id VARCHAR(36) REFERENCES games (id) ON DELETE CASCADE,
+    user_id INT REFERENCES users (id),
+    commitment VARCHAR(64) NOT NULL,
+    seed BIGINT,
+    origin_row INT,
+    origin_col INT,
+    layout TEXT,
+    CONSTRAINT unique_commitment_game_user UNIQUE (game_id, user_id)
+);
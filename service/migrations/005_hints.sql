@@ -0,0 +1,2 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS hints INT DEFAULT 3;
+ALTER TABLE players ADD COLUMN IF NOT EXISTS hints_used INT DEFAULT 0;
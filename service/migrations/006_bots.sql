@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_bot BOOLEAN DEFAULT false;
+
+-- Пароль '!' не является bcrypt-хешем, поэтому под ботами нельзя войти
+INSERT INTO users (username, password, is_bot) VALUES
+    ('bot:easy', '!', true),
+    ('bot:medium', '!', true),
+    ('bot:hard', '!', true)
+ON CONFLICT (username) DO NOTHING;
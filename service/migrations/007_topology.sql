@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS topology VARCHAR(15) DEFAULT 'square';
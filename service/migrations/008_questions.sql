@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS questions BOOLEAN DEFAULT false;
@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS lives INTEGER DEFAULT 1;
@@ -0,0 +1,3 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS coop BOOLEAN DEFAULT false;
+ALTER TABLE players ADD COLUMN IF NOT EXISTS moves INTEGER DEFAULT 0;
+ALTER TABLE players ADD COLUMN IF NOT EXISTS cells_opened INTEGER DEFAULT 0;
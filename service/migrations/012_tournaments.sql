@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS tournaments (
+    id VARCHAR(36) PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    organizer_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    format VARCHAR(31) NOT NULL,
+    preset VARCHAR(31) NOT NULL,
+    round_minutes INT NOT NULL,
+    rounds INT DEFAULT 0,
+    current_round INT DEFAULT 0,
+    round_deadline TIMESTAMP,
+    status VARCHAR(31) DEFAULT 'open',
+    winner_id INT REFERENCES users (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tournament_players (
+    tournament_id VARCHAR(36) REFERENCES tournaments (id) ON DELETE CASCADE,
+    user_id INT REFERENCES users (id),
+    seed INT NOT NULL,
+    wins INT DEFAULT 0,
+    draws INT DEFAULT 0,
+    losses INT DEFAULT 0,
+    byes INT DEFAULT 0,
+    eliminated BOOLEAN DEFAULT false,
+    CONSTRAINT unique_tournament_player UNIQUE (tournament_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS tournament_matches (
+    id SERIAL PRIMARY KEY,
+    tournament_id VARCHAR(36) REFERENCES tournaments (id) ON DELETE CASCADE,
+    round INT NOT NULL,
+    game_id VARCHAR(36) REFERENCES games (id) ON DELETE SET NULL,
+    player1_id INT REFERENCES users (id),
+    player2_id INT REFERENCES users (id),
+    winner_id INT REFERENCES users (id),
+    status VARCHAR(31) DEFAULT 'pending'
+);
+CREATE INDEX idx_tournament_matches_game_id ON tournament_matches (game_id);
+CREATE INDEX idx_tournament_matches_round ON tournament_matches (tournament_id, round);
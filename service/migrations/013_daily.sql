@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS daily_challenges (
+    day DATE PRIMARY KEY,
+    seed BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS daily_attempts (
+    day DATE REFERENCES daily_challenges (day) ON DELETE CASCADE,
+    user_id INT REFERENCES users (id) ON DELETE CASCADE,
+    game_id VARCHAR(36) REFERENCES games (id) ON DELETE SET NULL,
+    started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    won BOOLEAN DEFAULT false,
+    moves INT DEFAULT 0,
+    CONSTRAINT unique_daily_attempt UNIQUE (day, user_id)
+);
+CREATE INDEX idx_daily_attempts_game_id ON daily_attempts (game_id);
+
+ALTER TABLE games ADD COLUMN IF NOT EXISTS daily DATE;
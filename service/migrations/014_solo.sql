@@ -0,0 +1,2 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS solo BOOLEAN DEFAULT false;
+UPDATE games SET solo = true WHERE daily IS NOT NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id VARCHAR(36) PRIMARY KEY,
+    game_id VARCHAR(36) REFERENCES games (id) ON DELETE CASCADE,
+    creator_id INT REFERENCES users (id),
+    creator_username VARCHAR(255) NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_chat_messages_game_id ON chat_messages (game_id, created_at);
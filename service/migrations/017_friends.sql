@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS friends (
+    user_id INT REFERENCES users (id) ON DELETE CASCADE,
+    friend_id INT REFERENCES users (id) ON DELETE CASCADE,
+    status VARCHAR(31) DEFAULT 'pending',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, friend_id)
+);
+-- между двумя пользователями может быть только одна запись, кто бы ни отправил заявку
+CREATE UNIQUE INDEX idx_friends_pair ON friends (LEAST(user_id, friend_id), GREATEST(user_id, friend_id));
+CREATE INDEX idx_friends_friend_id ON friends (friend_id);
+
+CREATE TABLE IF NOT EXISTS direct_messages (
+    id SERIAL PRIMARY KEY,
+    sender_id INT REFERENCES users (id) ON DELETE CASCADE,
+    recipient_id INT REFERENCES users (id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_direct_messages_pair ON direct_messages (LEAST(sender_id, recipient_id), GREATEST(sender_id, recipient_id), id);